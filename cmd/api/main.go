@@ -1,18 +1,27 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	nethttp "net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/warley004/packing-optimizer-api/internal/api/http"
+	"github.com/warley004/packing-optimizer-api/internal/catalog"
+	"github.com/warley004/packing-optimizer-api/internal/config"
+	"github.com/warley004/packing-optimizer-api/internal/metrics"
+	"github.com/warley004/packing-optimizer-api/internal/packing"
+	"github.com/warley004/packing-optimizer-api/internal/service"
 
 	_ "github.com/warley004/packing-optimizer-api/docs"
-
 )
 
-
 // @title           Packing Optimizer API
 // @version         1.0
 // @description     API para otimizar o empacotamento de produtos em caixas disponíveis (minimizando o número de caixas).
@@ -26,15 +35,87 @@ func main() {
 		// Keep default debug for local dev; Docker/CI can set GIN_MODE=release
 	}
 
+	cfg, err := config.Load(os.Args[1:])
+	if err != nil {
+		log.Fatalf("invalid configuration: %v", err)
+	}
+
+	// Sem catálogo configurado, usa as caixas do enunciado; catálogo inválido impede a subida (fail fast).
+	boxes := packing.AvailableBoxes()
+	if cfg.BoxCatalogPath != "" {
+		boxes, err = catalog.LoadFile(cfg.BoxCatalogPath)
+		if err != nil {
+			log.Fatalf("box catalog: %v", err)
+		}
+		log.Printf("loaded %d box types from %s", len(boxes), cfg.BoxCatalogPath)
+	}
+
+	repo, err := newBoxRepository(cfg)
+	if err != nil {
+		log.Fatalf("box store: %v", err)
+	}
+	defer repo.Close()
+
+	// O arquivo/padrão só popula um repositório vazio; um BoltDB existente mantém as alterações feitas via API.
+	seeded, err := catalog.Seed(repo, boxes)
+	if err != nil {
+		log.Fatalf("box store seed: %v", err)
+	}
+	if seeded {
+		log.Printf("seeded %s box store with %d box types", cfg.BoxStore, len(boxes))
+	}
+
+	// O middleware de métricas fica por fora do Recovery para contar também os 500 de panics recuperados.
+	appMetrics := metrics.New()
 	router := gin.New()
-	router.Use(gin.Recovery())
+	router.Use(appMetrics.Middleware(), gin.Recovery())
 
-	http.RegisterRoutes(router)
+	packingService := service.NewPackingService(repo, service.Settings{
+		ExactMaxItems:  cfg.ExactMaxItems,
+		RequestTimeout: cfg.RequestTimeout,
+		PoolWorkers:    cfg.PoolWorkers,
+		PoolQueueSize:  cfg.PoolQueueSize,
+		Metrics:        appMetrics,
+	})
+	jobs := service.NewJobManager(packingService, service.JobSettings{
+		QueueSize: cfg.JobQueueSize,
+		ResultTTL: cfg.JobResultTTL,
+	})
+	http.RegisterRoutes(router, repo, packingService, jobs, appMetrics)
 
 	addr := ":8080"
+	srv := &nethttp.Server{Addr: addr, Handler: router}
 	log.Printf("starting server on %s", addr)
 
-	if err := router.Run(addr); err != nil {
-		log.Fatalf("server failed: %v", err)
+	// Desligamento gracioso: para de aceitar requests, espera os em andamento e só então encerra o pool de
+	// workers (e, pelos defers, o catálogo).
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		if !errors.Is(err, nethttp.ErrServerClosed) {
+			log.Fatalf("server failed: %v", err)
+		}
+	case <-ctx.Done():
+		log.Printf("shutting down")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Printf("server shutdown: %v", err)
+		}
+	}
+	packingService.Close()
+}
+
+func newBoxRepository(cfg config.Config) (catalog.Repository, error) {
+	if cfg.BoxStore == config.BoxStoreBolt {
+		return catalog.NewBoltRepository(cfg.BoxStorePath)
 	}
+	return catalog.NewMemoryRepository(), nil
 }
@@ -15,6 +15,216 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/v1/boxes": {
+            "get": {
+                "description": "Lista o catálogo de caixas. Por padrão apenas caixas ativas; use incluir_aposentadas=true para ver também as aposentadas.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "boxes"
+                ],
+                "summary": "Listar caixas",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Inclui caixas aposentadas",
+                        "name": "incluir_aposentadas",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CatalogoResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Adiciona uma caixa ao catálogo; ela passa a ser considerada nos próximos empacotamentos.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "boxes"
+                ],
+                "summary": "Cadastrar caixa",
+                "parameters": [
+                    {
+                        "description": "Caixa a cadastrar",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CaixaRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CaixaCatalogoResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Erro de validação do JSON/estrutura",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "caixa_id já cadastrado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/boxes/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "boxes"
+                ],
+                "summary": "Consultar caixa",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID da caixa",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CaixaCatalogoResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Caixa não encontrada",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Substitui dimensões, peso máximo, tara e custo de uma caixa existente, mantendo seu estado (ativa/aposentada).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "boxes"
+                ],
+                "summary": "Atualizar caixa",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID da caixa",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Novos dados da caixa",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.AtualizarCaixaRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CaixaCatalogoResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Erro de validação do JSON/estrutura",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Caixa não encontrada",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Retira a caixa do empacotamento sem apagá-la do catálogo. Não é permitido aposentar a última caixa ativa.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "boxes"
+                ],
+                "summary": "Aposentar caixa",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID da caixa",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CaixaCatalogoResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Caixa não encontrada",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Última caixa ativa",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
         "/v1/packing": {
             "post": {
                 "description": "Processa uma lista de pedidos e retorna a alocação de produtos em caixas disponíveis (minimizando o número de caixas).",
@@ -46,43 +256,536 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.PackingResponse"
                         }
                     },
+                    "207": {
+                        "description": "Modo melhor_esforco com pedidos que não puderam ser empacotados (status erro)",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PackingResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Erro de validação do JSON/estrutura (inclui caixas inline inválidas)",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "422": {
+                        "description": "Erro de empacotamento (produto não cabe) ou caixas_permitidas desconhecidas, no modo falhar_rapido",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Erro interno",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "503": {
+                        "description": "Pool de empacotamento saturado (POOL_SATURATED)",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "504": {
+                        "description": "Empacotamento excedeu o tempo limite do servidor (PACKING_TIMEOUT)",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/packing/jobs": {
+            "post": {
+                "description": "Enfileira um lote (mesmo formato de POST /v1/packing) para processamento assíncrono; acompanhe o progresso e obtenha o resultado em GET /v1/packing/jobs/{id}.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "packing"
+                ],
+                "summary": "Criar job de empacotamento",
+                "parameters": [
+                    {
+                        "description": "Lista de pedidos com produtos e dimensões",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PackingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/dto.JobResponse"
+                        }
+                    },
                     "400": {
                         "description": "Erro de validação do JSON/estrutura",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
-                    },
-                    "422": {
-                        "description": "Erro de empacotamento (produto não cabe)",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                    },
+                    "503": {
+                        "description": "Fila de jobs cheia",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/packing/jobs/{id}": {
+            "get": {
+                "description": "Devolve status e progresso do job; com status \"concluido\", inclui o resultado do lote. Jobs finalizados ficam disponíveis até expira_em.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "packing"
+                ],
+                "summary": "Consultar job de empacotamento",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do job",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.JobResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Job não encontrado ou expirado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Cancela um job na fila ou em processamento; pedidos já iniciados terminam, mas o lote não gera resultado.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "packing"
+                ],
+                "summary": "Cancelar job de empacotamento",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do job",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.JobResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Job não encontrado ou expirado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Job já finalizado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/packing/stream": {
+            "post": {
+                "description": "Recebe um PedidoRequest por linha (application/x-ndjson) e devolve um PedidoResponse por linha assim que cada pedido é empacotado, na ordem de conclusão (use pedido_id para correlacionar). Pedidos inválidos ou que não cabem voltam com status \"erro\" e o fluxo continua; um JSON malformado encerra o fluxo com uma última linha {\"error\": {...}}. As opções de lote vão na query string.",
+                "consumes": [
+                    "application/x-ndjson"
+                ],
+                "produces": [
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "packing"
+                ],
+                "summary": "Empacotar pedidos em streaming (NDJSON)",
+                "parameters": [
+                    {
+                        "description": "Um pedido por linha",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PedidoRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ffd, bfd, camadas ou melhor",
+                        "name": "algoritmo",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "guilhotina ou espacos_maximais",
+                        "name": "estrategia_espacos",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "caixas ou custo",
+                        "name": "objetivo",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Empacota o que couber",
+                        "name": "permitir_parcial",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Folga padrão dos produtos",
+                        "name": "folga_padrao",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Percentual mínimo da base apoiada (0 a 100)",
+                        "name": "apoio_minimo",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Desempata posições pelo centro de gravidade",
+                        "name": "balancear",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Inclui posições dos produtos",
+                        "name": "incluir_posicoes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Inclui espaços livres das caixas",
+                        "name": "incluir_espacos_livres",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Restringe as caixas do catálogo",
+                        "name": "caixas_permitidas",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "modelo_custo: divisor do peso cubado",
+                        "name": "divisor_peso_cubado",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "modelo_custo: frete por kg tarifável",
+                        "name": "valor_por_kg",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "modelo_custo: preenchimento por volume vazio",
+                        "name": "custo_preenchimento",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Uma linha por pedido",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PedidoResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Query inválida ou primeira linha malformada",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "422": {
+                        "description": "caixas_permitidas desconhecidas",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "503": {
+                        "description": "Pool de empacotamento saturado (POOL_SATURATED)",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "dto.AtualizarCaixaRequest": {
+            "type": "object",
+            "required": [
+                "dimensoes"
+            ],
+            "properties": {
+                "custo": {
+                    "description": "Custo é o preço unitário da caixa, usado pelo objetivo \"custo\".",
+                    "type": "number",
+                    "minimum": 0
+                },
+                "dimensoes": {
+                    "description": "Dimensoes são as dimensões internas, onde os produtos são alocados.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.DimensoesDTO"
+                        }
+                    ]
+                },
+                "dimensoes_externas": {
+                    "description": "DimensoesExternas são as dimensões para transporte; sem elas, valem as internas mais 2× EspessuraParede.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.DimensoesDTO"
+                        }
+                    ]
+                },
+                "espessura_parede": {
+                    "description": "EspessuraParede é somada duas vezes a cada dimensão interna para obter as externas.",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "peso_maximo": {
+                    "description": "PesoMaximo (kg) limita o conteúdo da caixa; zero/ausente significa sem limite.",
+                    "type": "number",
+                    "minimum": 0
+                },
+                "tara": {
+                    "description": "Tara (kg) é o peso da caixa vazia.",
+                    "type": "number",
+                    "minimum": 0
+                }
+            }
+        },
+        "dto.CaixaCatalogoResponse": {
+            "type": "object",
+            "properties": {
+                "ativa": {
+                    "type": "boolean"
+                },
+                "caixa_id": {
+                    "type": "string"
+                },
+                "custo": {
+                    "type": "number"
+                },
+                "dimensoes": {
+                    "$ref": "#/definitions/dto.DimensoesDTO"
+                },
+                "dimensoes_externas": {
+                    "description": "DimensoesExternas já considera a espessura da parede.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.DimensoesDTO"
+                        }
+                    ]
+                },
+                "espessura_parede": {
+                    "type": "integer"
+                },
+                "peso_maximo": {
+                    "type": "number"
+                },
+                "tara": {
+                    "type": "number"
+                }
+            }
+        },
+        "dto.CaixaRequest": {
+            "type": "object",
+            "required": [
+                "caixa_id",
+                "dimensoes"
+            ],
+            "properties": {
+                "caixa_id": {
+                    "type": "string",
+                    "minLength": 1
+                },
+                "custo": {
+                    "description": "Custo é o preço unitário da caixa, usado pelo objetivo \"custo\".",
+                    "type": "number",
+                    "minimum": 0
+                },
+                "dimensoes": {
+                    "description": "Dimensoes são as dimensões internas, onde os produtos são alocados.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.DimensoesDTO"
                         }
-                    },
-                    "500": {
-                        "description": "Erro interno",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                    ]
+                },
+                "dimensoes_externas": {
+                    "description": "DimensoesExternas são as dimensões para transporte; sem elas, valem as internas mais 2× EspessuraParede.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.DimensoesDTO"
                         }
-                    }
+                    ]
+                },
+                "espessura_parede": {
+                    "description": "EspessuraParede é somada duas vezes a cada dimensão interna para obter as externas.",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "peso_maximo": {
+                    "description": "PesoMaximo (kg) limita o conteúdo da caixa; zero/ausente significa sem limite.",
+                    "type": "number",
+                    "minimum": 0
+                },
+                "tara": {
+                    "description": "Tara (kg) é o peso da caixa vazia.",
+                    "type": "number",
+                    "minimum": 0
                 }
             }
-        }
-    },
-    "definitions": {
+        },
         "dto.CaixaResponse": {
             "type": "object",
             "properties": {
                 "caixa_id": {
                     "type": "string"
                 },
+                "centro_gravidade": {
+                    "description": "CentroGravidade do conteúdo, com origem no canto interno da caixa (mesmos eixos de posicao); ponderado pelo peso\ndos produtos ou, sem pesos, pelo volume.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.PontoDTO"
+                        }
+                    ]
+                },
+                "custo_estimado": {
+                    "description": "CustoEstimado só é preenchido com objetivo \"custo\".",
+                    "type": "number"
+                },
+                "dimensoes_externas": {
+                    "description": "DimensoesExternas e VolumeExterno descrevem a caixa fechada, para etiqueta e cotação de frete.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.DimensoesDTO"
+                        }
+                    ]
+                },
+                "espacos_livres": {
+                    "description": "EspacosLivres só é preenchido quando o request pede incluir_espacos_livres. Com espacos_maximais\nos espaços podem se sobrepor, então seus volumes não devem ser somados.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.EspacoLivreResponse"
+                    }
+                },
+                "ocupacao_percentual": {
+                    "description": "OcupacaoPercentual é VolumeUsado sobre VolumeCaixa, de 0 a 100.",
+                    "type": "number"
+                },
+                "peso_bruto": {
+                    "description": "PesoBruto (kg) soma tara e produtos; omitido quando nenhum peso é conhecido.",
+                    "type": "number"
+                },
+                "posicoes": {
+                    "description": "Posicoes só é preenchido quando o request pede incluir_posicoes.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PosicaoProdutoResponse"
+                    }
+                },
                 "produtos": {
+                    "description": "Produtos lista cada produto do pedido presente na caixa uma vez; Quantidades informa quantas unidades de cada produto_id foram para ela.",
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
+                },
+                "quantidades": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "volume_caixa": {
+                    "description": "VolumeCaixa é o volume interno; VolumeUsado soma o espaço ocupado pelos produtos, folga incluída.",
+                    "type": "integer"
+                },
+                "volume_externo": {
+                    "type": "integer"
+                },
+                "volume_usado": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.CatalogoResponse": {
+            "type": "object",
+            "properties": {
+                "caixas": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CaixaCatalogoResponse"
+                    }
+                }
+            }
+        },
+        "dto.CoordenadasDTO": {
+            "type": "object",
+            "properties": {
+                "x": {
+                    "type": "integer"
+                },
+                "y": {
+                    "type": "integer"
+                },
+                "z": {
+                    "type": "integer"
                 }
             }
         },
@@ -105,18 +808,213 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.ErroResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.EspacoLivreResponse": {
+            "type": "object",
+            "properties": {
+                "dimensoes": {
+                    "$ref": "#/definitions/dto.DimensoesDTO"
+                },
+                "posicao": {
+                    "$ref": "#/definitions/dto.CoordenadasDTO"
+                }
+            }
+        },
+        "dto.JobResponse": {
+            "type": "object",
+            "properties": {
+                "criado_em": {
+                    "type": "string"
+                },
+                "erro": {
+                    "$ref": "#/definitions/dto.ErroResponse"
+                },
+                "expira_em": {
+                    "description": "ExpiraEm é quando um job finalizado e seu resultado deixam de ser consultáveis.",
+                    "type": "string"
+                },
+                "finalizado_em": {
+                    "type": "string"
+                },
+                "iniciado_em": {
+                    "description": "IniciadoEm e FinalizadoEm são preenchidos quando o job sai da fila e quando termina (com qualquer status final).",
+                    "type": "string"
+                },
+                "job_id": {
+                    "type": "string"
+                },
+                "progresso": {
+                    "$ref": "#/definitions/dto.ProgressoJobResponse"
+                },
+                "resultado": {
+                    "description": "Resultado só é preenchido com status \"concluido\"; tem o mesmo formato da resposta de POST /v1/packing.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.PackingResponse"
+                        }
+                    ]
+                },
+                "status": {
+                    "description": "Status é \"na_fila\", \"processando\", \"concluido\", \"falhou\" (Erro explica o motivo) ou \"cancelado\".",
+                    "type": "string",
+                    "enum": [
+                        "na_fila",
+                        "processando",
+                        "concluido",
+                        "falhou",
+                        "cancelado"
+                    ]
+                }
+            }
+        },
+        "dto.LimitesInferioresResponse": {
+            "type": "object",
+            "properties": {
+                "dimensional": {
+                    "description": "Dimensional: limites L1/L2 de Martello, Pisinger e Vigo sobre produtos maiores que metade da caixa em duas dimensões.",
+                    "type": "integer"
+                },
+                "incompatibilidade": {
+                    "description": "Incompatibilidade: produtos que, dois a dois, não cabem juntos em nenhuma caixa (clique montada de forma gulosa).",
+                    "type": "integer"
+                },
+                "peso": {
+                    "description": "Peso: peso dos produtos sobre a maior carga máxima (zero se alguma caixa não tem limite).",
+                    "type": "integer"
+                },
+                "volume": {
+                    "description": "Volume: volume dos produtos sobre o da maior caixa.",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.ModeloCustoDTO": {
+            "type": "object",
+            "properties": {
+                "custo_preenchimento": {
+                    "description": "CustoPreenchimento é o custo do material de preenchimento por unidade de volume vazio.",
+                    "type": "number",
+                    "minimum": 0
+                },
+                "divisor_peso_cubado": {
+                    "description": "DivisorPesoCubado converte volume em peso cubado (ex.: 6000 para cm³/kg); zero ignora o peso cubado.",
+                    "type": "number",
+                    "minimum": 0
+                },
+                "valor_por_kg": {
+                    "description": "ValorPorKg é o frete por kg tarifável (maior entre peso bruto e peso cubado).",
+                    "type": "number",
+                    "minimum": 0
+                }
+            }
+        },
         "dto.PackingRequest": {
             "type": "object",
             "required": [
+                "caixas_permitidas",
                 "pedidos"
             ],
             "properties": {
+                "algoritmo": {
+                    "description": "Algoritmo escolhe a estratégia de empacotamento: \"ffd\" (padrão, primeira caixa que couber), \"bfd\" (caixa mais cheia),\n\"camadas\" (montagem em camadas) ou \"melhor\" (roda todas e fica com o melhor resultado).",
+                    "type": "string",
+                    "enum": [
+                        "ffd",
+                        "bfd",
+                        "camadas",
+                        "melhor"
+                    ]
+                },
+                "apoio_minimo": {
+                    "description": "ApoioMinimo é o percentual mínimo (0 a 100) da base de cada produto que precisa estar apoiado no fundo da caixa\nou sobre outros produtos; ausente/zero não verifica o apoio.",
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "balancear": {
+                    "description": "Balancear desempata posições de mesmo desperdício pela que mantém o centro de gravidade mais perto do centro da caixa.",
+                    "type": "boolean"
+                },
+                "caixas": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/dto.CaixaRequest"
+                    }
+                },
+                "caixas_permitidas": {
+                    "description": "CaixasPermitidas e Caixas definem o conjunto de caixas padrão de todos os pedidos do lote (ver PedidoRequest).",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "estrategia_espacos": {
+                    "description": "EstrategiaEspacos escolhe o gerenciador de espaços livres: \"guilhotina\" (padrão) ou \"espacos_maximais\".",
+                    "type": "string",
+                    "enum": [
+                        "guilhotina",
+                        "espacos_maximais"
+                    ]
+                },
+                "folga_padrao": {
+                    "description": "FolgaPadrao é a folga de proteção aplicada em cada face dos produtos que não informam folga.",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "incluir_espacos_livres": {
+                    "description": "IncluirEspacosLivres habilita (opt-in) o retorno dos espaços livres que sobraram em cada caixa.",
+                    "type": "boolean"
+                },
+                "incluir_posicoes": {
+                    "description": "IncluirPosicoes habilita (opt-in) o retorno da posição e orientação de cada produto dentro das caixas.",
+                    "type": "boolean"
+                },
+                "modelo_custo": {
+                    "description": "ModeloCusto parametriza o objetivo \"custo\"; sem ele, só o custo unitário das caixas é considerado.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.ModeloCustoDTO"
+                        }
+                    ]
+                },
+                "modo": {
+                    "description": "Modo define o que acontece quando um pedido não pode ser empacotado: \"falhar_rapido\" (padrão) rejeita o lote\ninteiro com o primeiro erro; \"melhor_esforco\" empacota os demais pedidos e marca os que falharam (HTTP 207).",
+                    "type": "string",
+                    "enum": [
+                        "falhar_rapido",
+                        "melhor_esforco"
+                    ]
+                },
+                "objetivo": {
+                    "description": "Objetivo escolhe o que minimizar: \"caixas\" (padrão, menos caixas) ou \"custo\" (menor custo estimado).",
+                    "type": "string",
+                    "enum": [
+                        "caixas",
+                        "custo"
+                    ]
+                },
                 "pedidos": {
                     "type": "array",
                     "minItems": 1,
                     "items": {
                         "$ref": "#/definitions/dto.PedidoRequest"
                     }
+                },
+                "permitir_parcial": {
+                    "description": "PermitirParcial empacota o que couber e lista em nao_empacotados os produtos que não cabem em nenhuma caixa,\nem vez de falhar o pedido.",
+                    "type": "boolean"
                 }
             }
         },
@@ -128,16 +1026,37 @@ const docTemplate = `{
                     "items": {
                         "$ref": "#/definitions/dto.PedidoResponse"
                     }
+                },
+                "pedidos_com_erro": {
+                    "description": "PedidosComErro conta os pedidos com status \"erro\"; só é diferente de zero no modo \"melhor_esforco\".",
+                    "type": "integer"
                 }
             }
         },
         "dto.PedidoRequest": {
             "type": "object",
             "required": [
+                "caixas_permitidas",
                 "pedido_id",
                 "produtos"
             ],
             "properties": {
+                "caixas": {
+                    "description": "Caixas substitui o catálogo por caixas definidas no próprio pedido. Não pode ser combinado com CaixasPermitidas.\nQuando informado no pedido, prevalece sobre o definido no nível do request.",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/dto.CaixaRequest"
+                    }
+                },
+                "caixas_permitidas": {
+                    "description": "CaixasPermitidas restringe o pedido a um subconjunto do catálogo (por caixa_id).",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "pedido_id": {
                     "type": "integer"
                 },
@@ -159,8 +1078,104 @@ const docTemplate = `{
                         "$ref": "#/definitions/dto.CaixaResponse"
                     }
                 },
+                "custo_estimado": {
+                    "description": "CustoEstimado só é preenchido com objetivo \"custo\".",
+                    "type": "number"
+                },
+                "erro": {
+                    "$ref": "#/definitions/dto.ErroResponse"
+                },
+                "nao_empacotados": {
+                    "description": "NaoEmpacotados lista os produtos deixados de fora com permitir_parcial, na ordem do pedido.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ProdutoNaoEmpacotadoResponse"
+                    }
+                },
+                "otimo_comprovado": {
+                    "description": "OtimoComprovado indica que o número de caixas é comprovadamente o mínimo possível\n(atinge o limite inferior ou foi confirmado pela busca exata).",
+                    "type": "boolean"
+                },
                 "pedido_id": {
                     "type": "integer"
+                },
+                "resumo": {
+                    "description": "Resumo consolida as métricas de ocupação das caixas do pedido.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.ResumoPedidoResponse"
+                        }
+                    ]
+                },
+                "status": {
+                    "description": "Status é \"ok\", \"parcial\" (com permitir_parcial, há produtos em NaoEmpacotados) ou \"erro\";\ncom \"erro\", Caixas vem vazio e Erro explica o motivo.",
+                    "type": "string",
+                    "enum": [
+                        "ok",
+                        "parcial",
+                        "erro"
+                    ]
+                }
+            }
+        },
+        "dto.PontoDTO": {
+            "type": "object",
+            "properties": {
+                "x": {
+                    "type": "number"
+                },
+                "y": {
+                    "type": "number"
+                },
+                "z": {
+                    "type": "number"
+                }
+            }
+        },
+        "dto.PosicaoProdutoResponse": {
+            "type": "object",
+            "properties": {
+                "dimensoes_com_folga": {
+                    "$ref": "#/definitions/dto.DimensoesDTO"
+                },
+                "folga": {
+                    "type": "integer"
+                },
+                "orientacao": {
+                    "$ref": "#/definitions/dto.DimensoesDTO"
+                },
+                "posicao": {
+                    "$ref": "#/definitions/dto.CoordenadasDTO"
+                },
+                "produto_id": {
+                    "type": "string"
+                },
+                "rotacionado": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.ProdutoNaoEmpacotadoResponse": {
+            "type": "object",
+            "properties": {
+                "mensagem": {
+                    "type": "string"
+                },
+                "motivo": {
+                    "description": "Motivo: \"muito_grande\" (não cabe em nenhuma orientação), \"excede_peso\" (acima da carga máxima das caixas\nem que cabe) ou \"orientacao_bloqueada\" (só caberia girado além do permitido pela orientação).",
+                    "type": "string",
+                    "enum": [
+                        "muito_grande",
+                        "excede_peso",
+                        "orientacao_bloqueada"
+                    ]
+                },
+                "produto_id": {
+                    "type": "string"
+                },
+                "quantidade": {
+                    "description": "Quantidade de unidades do produto que ficaram de fora.",
+                    "type": "integer"
                 }
             }
         },
@@ -171,12 +1186,93 @@ const docTemplate = `{
                 "produto_id"
             ],
             "properties": {
+                "carga_maxima_topo": {
+                    "description": "CargaMaximaTopo (kg) limita o peso apoiado sobre o produto, somando toda a pilha acima dele; ausente = sem limite.",
+                    "type": "number",
+                    "minimum": 0
+                },
                 "dimensoes": {
                     "$ref": "#/definitions/dto.DimensoesDTO"
                 },
+                "folga": {
+                    "description": "Folga é a margem de proteção (ex.: plástico bolha) somada em cada face do produto; ausente usa folga_padrao.",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "fragil": {
+                    "description": "Fragil impede que qualquer produto seja apoiado sobre este.",
+                    "type": "boolean"
+                },
+                "orientacao": {
+                    "description": "Orientacao restringe as rotações do produto: \"livre\" (padrão), \"vertical\" (altura sempre na vertical) ou \"fixa\".",
+                    "type": "string",
+                    "enum": [
+                        "livre",
+                        "vertical",
+                        "fixa"
+                    ]
+                },
+                "peso": {
+                    "description": "Peso (kg) é opcional. É usado no limite peso_maximo das caixas, na carga_maxima_topo dos produtos abaixo,\nno centro de gravidade e no balancear, no peso_bruto e no frete do objetivo \"custo\".",
+                    "type": "number",
+                    "minimum": 0
+                },
                 "produto_id": {
                     "type": "string",
                     "minLength": 1
+                },
+                "quantidade": {
+                    "description": "Quantidade de unidades idênticas do produto (padrão 1); evita repetir o mesmo produto no pedido.",
+                    "type": "integer",
+                    "maximum": 10000,
+                    "minimum": 1
+                }
+            }
+        },
+        "dto.ProgressoJobResponse": {
+            "type": "object",
+            "properties": {
+                "pedidos_processados": {
+                    "type": "integer"
+                },
+                "percentual": {
+                    "description": "Percentual vai de 0 a 100.",
+                    "type": "number"
+                },
+                "total_pedidos": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.ResumoPedidoResponse": {
+            "type": "object",
+            "properties": {
+                "gap": {
+                    "description": "Gap é TotalCaixas - LimiteInferior; GapPercentual é o gap sobre o limite inferior (0 a 100+).\nGap zero comprova o ótimo; gaps altos indicam pedidos em que outra estratégia pode valer a pena.",
+                    "type": "integer"
+                },
+                "gap_percentual": {
+                    "type": "number"
+                },
+                "limite_inferior": {
+                    "description": "LimiteInferior é o mínimo de caixas que qualquer solução precisaria: o maior dos LimitesInferiores ou, com o\nótimo comprovado pela busca exata, o próprio TotalCaixas.",
+                    "type": "integer"
+                },
+                "limites_inferiores": {
+                    "$ref": "#/definitions/dto.LimitesInferioresResponse"
+                },
+                "ocupacao_media": {
+                    "description": "OcupacaoMedia é a média simples do percentual de ocupação das caixas (0 a 100).",
+                    "type": "number"
+                },
+                "total_caixas": {
+                    "type": "integer"
+                },
+                "volume_total": {
+                    "type": "integer"
+                },
+                "volume_usado": {
+                    "type": "integer"
                 }
             }
         }
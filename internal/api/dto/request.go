@@ -2,20 +2,124 @@ package dto
 
 type PackingRequest struct {
 	Pedidos []PedidoRequest `json:"pedidos" binding:"required,min=1"`
+	// Modo define o que acontece quando um pedido não pode ser empacotado: "falhar_rapido" (padrão) rejeita o lote
+	// inteiro com o primeiro erro; "melhor_esforco" empacota os demais pedidos e marca os que falharam (HTTP 207).
+	Modo string `json:"modo" binding:"omitempty,oneof=falhar_rapido melhor_esforco" enums:"falhar_rapido,melhor_esforco"`
+	// PermitirParcial empacota o que couber e lista em nao_empacotados os produtos que não cabem em nenhuma caixa,
+	// em vez de falhar o pedido.
+	PermitirParcial bool `json:"permitir_parcial"`
+	// FolgaPadrao é a folga de proteção aplicada em cada face dos produtos que não informam folga.
+	FolgaPadrao int `json:"folga_padrao,omitempty" binding:"omitempty,gte=0"`
+	// ApoioMinimo é o percentual mínimo (0 a 100) da base de cada produto que precisa estar apoiado no fundo da caixa
+	// ou sobre outros produtos; ausente/zero não verifica o apoio.
+	ApoioMinimo float64 `json:"apoio_minimo,omitempty" binding:"omitempty,gte=0,lte=100"`
+	// Balancear desempata posições de mesmo desperdício pela que mantém o centro de gravidade mais perto do centro da caixa.
+	Balancear bool `json:"balancear"`
+	// IncluirPosicoes habilita (opt-in) o retorno da posição e orientação de cada produto dentro das caixas.
+	IncluirPosicoes bool `json:"incluir_posicoes"`
+	// IncluirEspacosLivres habilita (opt-in) o retorno dos espaços livres que sobraram em cada caixa.
+	IncluirEspacosLivres bool `json:"incluir_espacos_livres"`
+	// EstrategiaEspacos escolhe o gerenciador de espaços livres: "guilhotina" (padrão) ou "espacos_maximais".
+	EstrategiaEspacos string `json:"estrategia_espacos" binding:"omitempty,oneof=guilhotina espacos_maximais" enums:"guilhotina,espacos_maximais"`
+	// Algoritmo escolhe a estratégia de empacotamento: "ffd" (padrão, primeira caixa que couber), "bfd" (caixa mais cheia),
+	// "camadas" (montagem em camadas) ou "melhor" (roda todas e fica com o melhor resultado).
+	Algoritmo string `json:"algoritmo" binding:"omitempty,oneof=ffd bfd camadas melhor" enums:"ffd,bfd,camadas,melhor"`
+	// Objetivo escolhe o que minimizar: "caixas" (padrão, menos caixas) ou "custo" (menor custo estimado).
+	Objetivo string `json:"objetivo" binding:"omitempty,oneof=caixas custo" enums:"caixas,custo"`
+	// ModeloCusto parametriza o objetivo "custo"; sem ele, só o custo unitário das caixas é considerado.
+	ModeloCusto *ModeloCustoDTO `json:"modelo_custo,omitempty"`
+	// CaixasPermitidas e Caixas definem o conjunto de caixas padrão de todos os pedidos do lote (ver PedidoRequest).
+	CaixasPermitidas []string       `json:"caixas_permitidas,omitempty" binding:"omitempty,min=1,dive,required"`
+	Caixas           []CaixaRequest `json:"caixas,omitempty" binding:"omitempty,min=1,dive"`
+}
+
+// ModeloCustoDTO descreve o custo de envio de uma caixa: custo da caixa + frete + preenchimento do espaço vazio.
+type ModeloCustoDTO struct {
+	// DivisorPesoCubado converte volume em peso cubado (ex.: 6000 para cm³/kg); zero ignora o peso cubado.
+	DivisorPesoCubado float64 `json:"divisor_peso_cubado" binding:"gte=0"`
+	// ValorPorKg é o frete por kg tarifável (maior entre peso bruto e peso cubado).
+	ValorPorKg float64 `json:"valor_por_kg" binding:"gte=0"`
+	// CustoPreenchimento é o custo do material de preenchimento por unidade de volume vazio.
+	CustoPreenchimento float64 `json:"custo_preenchimento" binding:"gte=0"`
 }
 
 type PedidoRequest struct {
-	PedidoID  int64            `json:"pedido_id" binding:"required"`
-	Produtos  []ProdutoRequest `json:"produtos" binding:"required,min=1"`
+	PedidoID int64            `json:"pedido_id" binding:"required"`
+	Produtos []ProdutoRequest `json:"produtos" binding:"required,min=1"`
+	// CaixasPermitidas restringe o pedido a um subconjunto do catálogo (por caixa_id).
+	CaixasPermitidas []string `json:"caixas_permitidas,omitempty" binding:"omitempty,min=1,dive,required"`
+	// Caixas substitui o catálogo por caixas definidas no próprio pedido. Não pode ser combinado com CaixasPermitidas.
+	// Quando informado no pedido, prevalece sobre o definido no nível do request.
+	Caixas []CaixaRequest `json:"caixas,omitempty" binding:"omitempty,min=1,dive"`
 }
 
 type ProdutoRequest struct {
-	ProdutoID  string        `json:"produto_id" binding:"required,min=1"`
-	Dimensoes  DimensoesDTO  `json:"dimensoes" binding:"required"`
+	ProdutoID string       `json:"produto_id" binding:"required,min=1"`
+	Dimensoes DimensoesDTO `json:"dimensoes" binding:"required"`
+	// Quantidade de unidades idênticas do produto (padrão 1); evita repetir o mesmo produto no pedido.
+	Quantidade int `json:"quantidade,omitempty" binding:"omitempty,min=1,max=10000"`
+	// Folga é a margem de proteção (ex.: plástico bolha) somada em cada face do produto; ausente usa folga_padrao.
+	Folga *int `json:"folga,omitempty" binding:"omitempty,gte=0"`
+	// Peso (kg) é opcional. É usado no limite peso_maximo das caixas, na carga_maxima_topo dos produtos abaixo,
+	// no centro de gravidade e no balancear, no peso_bruto e no frete do objetivo "custo".
+	Peso float64 `json:"peso,omitempty" binding:"omitempty,gte=0"`
+	// Fragil impede que qualquer produto seja apoiado sobre este.
+	Fragil bool `json:"fragil,omitempty"`
+	// CargaMaximaTopo (kg) limita o peso apoiado sobre o produto, somando toda a pilha acima dele; ausente = sem limite.
+	CargaMaximaTopo float64 `json:"carga_maxima_topo,omitempty" binding:"omitempty,gte=0"`
+	// Orientacao restringe as rotações do produto: "livre" (padrão), "vertical" (altura sempre na vertical) ou "fixa".
+	Orientacao string `json:"orientacao,omitempty" binding:"omitempty,oneof=livre vertical fixa" enums:"livre,vertical,fixa"`
 }
 
 type DimensoesDTO struct {
-	Altura      int `json:"altura" binding:"required,gt=0"`
-	Largura     int `json:"largura" binding:"required,gt=0"`
-	Comprimento int `json:"comprimento" binding:"required,gt=0"`
+	Altura      int `json:"altura" yaml:"altura" binding:"required,gt=0"`
+	Largura     int `json:"largura" yaml:"largura" binding:"required,gt=0"`
+	Comprimento int `json:"comprimento" yaml:"comprimento" binding:"required,gt=0"`
+}
+
+// CaixaDadosDTO reúne os atributos de uma caixa além do ID. As tags yaml servem ao arquivo de catálogo, que
+// aceita as mesmas caixas da API.
+type CaixaDadosDTO struct {
+	// Dimensoes são as dimensões internas, onde os produtos são alocados.
+	Dimensoes DimensoesDTO `json:"dimensoes" yaml:"dimensoes" binding:"required"`
+	// DimensoesExternas são as dimensões para transporte; sem elas, valem as internas mais 2× EspessuraParede.
+	DimensoesExternas *DimensoesDTO `json:"dimensoes_externas,omitempty" yaml:"dimensoes_externas"`
+	// EspessuraParede é somada duas vezes a cada dimensão interna para obter as externas.
+	EspessuraParede int `json:"espessura_parede,omitempty" yaml:"espessura_parede" binding:"omitempty,gte=0"`
+	// PesoMaximo (kg) limita o conteúdo da caixa; zero/ausente significa sem limite.
+	PesoMaximo float64 `json:"peso_maximo,omitempty" yaml:"peso_maximo" binding:"omitempty,gte=0"`
+	// Tara (kg) é o peso da caixa vazia.
+	Tara float64 `json:"tara,omitempty" yaml:"tara" binding:"omitempty,gte=0"`
+	// Custo é o preço unitário da caixa, usado pelo objetivo "custo".
+	Custo float64 `json:"custo,omitempty" yaml:"custo" binding:"omitempty,gte=0"`
+}
+
+// CaixaRequest cadastra uma nova caixa no catálogo ou define uma caixa inline em um pedido.
+type CaixaRequest struct {
+	CaixaID       string `json:"caixa_id" yaml:"caixa_id" binding:"required,min=1"`
+	CaixaDadosDTO `yaml:",inline"`
+}
+
+// AtualizarCaixaRequest substitui os dados de uma caixa existente; o ID vem da rota.
+type AtualizarCaixaRequest struct {
+	CaixaDadosDTO
+}
+
+// PackingStreamQuery traz, pela query string, as opções de lote do streaming NDJSON (o corpo só carrega pedidos).
+// Os campos têm o mesmo significado dos homônimos em PackingRequest.
+type PackingStreamQuery struct {
+	PermitirParcial      bool     `form:"permitir_parcial"`
+	FolgaPadrao          int      `form:"folga_padrao" binding:"omitempty,gte=0"`
+	ApoioMinimo          float64  `form:"apoio_minimo" binding:"omitempty,gte=0,lte=100"`
+	Balancear            bool     `form:"balancear"`
+	IncluirPosicoes      bool     `form:"incluir_posicoes"`
+	IncluirEspacosLivres bool     `form:"incluir_espacos_livres"`
+	EstrategiaEspacos    string   `form:"estrategia_espacos" binding:"omitempty,oneof=guilhotina espacos_maximais"`
+	Algoritmo            string   `form:"algoritmo" binding:"omitempty,oneof=ffd bfd camadas melhor"`
+	Objetivo             string   `form:"objetivo" binding:"omitempty,oneof=caixas custo"`
+	CaixasPermitidas     []string `form:"caixas_permitidas" binding:"omitempty,dive,required"`
+	// DivisorPesoCubado, ValorPorKg e CustoPreenchimento formam o modelo_custo do objetivo "custo".
+	DivisorPesoCubado  float64 `form:"divisor_peso_cubado" binding:"omitempty,gte=0"`
+	ValorPorKg         float64 `form:"valor_por_kg" binding:"omitempty,gte=0"`
+	CustoPreenchimento float64 `form:"custo_preenchimento" binding:"omitempty,gte=0"`
 }
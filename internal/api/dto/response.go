@@ -1,15 +1,188 @@
 package dto
 
+import "time"
+
 type PackingResponse struct {
 	Pedidos []PedidoResponse `json:"pedidos"`
+	// PedidosComErro conta os pedidos com status "erro"; só é diferente de zero no modo "melhor_esforco".
+	PedidosComErro int `json:"pedidos_com_erro"`
 }
 
+const (
+	PedidoStatusOK      = "ok"
+	PedidoStatusParcial = "parcial"
+	PedidoStatusErro    = "erro"
+)
+
 type PedidoResponse struct {
-	PedidoID int64          `json:"pedido_id"`
+	PedidoID int64           `json:"pedido_id"`
 	Caixas   []CaixaResponse `json:"caixas"`
+	// Status é "ok", "parcial" (com permitir_parcial, há produtos em NaoEmpacotados) ou "erro";
+	// com "erro", Caixas vem vazio e Erro explica o motivo.
+	Status string        `json:"status" enums:"ok,parcial,erro"`
+	Erro   *ErroResponse `json:"erro,omitempty"`
+	// NaoEmpacotados lista os produtos deixados de fora com permitir_parcial, na ordem do pedido.
+	NaoEmpacotados []ProdutoNaoEmpacotadoResponse `json:"nao_empacotados,omitempty"`
+	// CustoEstimado só é preenchido com objetivo "custo".
+	CustoEstimado *float64 `json:"custo_estimado,omitempty"`
+	// OtimoComprovado indica que o número de caixas é comprovadamente o mínimo possível
+	// (atinge o limite inferior ou foi confirmado pela busca exata).
+	OtimoComprovado bool `json:"otimo_comprovado"`
+	// Resumo consolida as métricas de ocupação das caixas do pedido.
+	Resumo ResumoPedidoResponse `json:"resumo"`
+}
+
+// ResumoPedidoResponse traz os totais do pedido; volumes são internos às caixas.
+type ResumoPedidoResponse struct {
+	TotalCaixas int `json:"total_caixas"`
+	VolumeTotal int `json:"volume_total"`
+	VolumeUsado int `json:"volume_usado"`
+	// OcupacaoMedia é a média simples do percentual de ocupação das caixas (0 a 100).
+	OcupacaoMedia float64 `json:"ocupacao_media"`
+	// LimiteInferior é o mínimo de caixas que qualquer solução precisaria: o maior dos LimitesInferiores ou, com o
+	// ótimo comprovado pela busca exata, o próprio TotalCaixas.
+	LimiteInferior    int                       `json:"limite_inferior"`
+	LimitesInferiores LimitesInferioresResponse `json:"limites_inferiores"`
+	// Gap é TotalCaixas - LimiteInferior; GapPercentual é o gap sobre o limite inferior (0 a 100+).
+	// Gap zero comprova o ótimo; gaps altos indicam pedidos em que outra estratégia pode valer a pena.
+	Gap           int     `json:"gap"`
+	GapPercentual float64 `json:"gap_percentual"`
+}
+
+// LimitesInferioresResponse detalha os limites inferiores calculados para o pedido.
+type LimitesInferioresResponse struct {
+	// Volume: volume dos produtos sobre o da maior caixa.
+	Volume int `json:"volume"`
+	// Peso: peso dos produtos sobre a maior carga máxima (zero se alguma caixa não tem limite).
+	Peso int `json:"peso"`
+	// Incompatibilidade: produtos que, dois a dois, não cabem juntos em nenhuma caixa (clique montada de forma gulosa).
+	Incompatibilidade int `json:"incompatibilidade"`
+	// Dimensional: limites L1/L2 de Martello, Pisinger e Vigo sobre produtos maiores que metade da caixa em duas dimensões.
+	Dimensional int `json:"dimensional"`
+}
+
+// ErroResponse segue o mesmo formato do corpo de erro da API ({"code", "message"}).
+type ErroResponse struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ProdutoNaoEmpacotadoResponse identifica um produto que não coube em nenhuma caixa e o motivo.
+type ProdutoNaoEmpacotadoResponse struct {
+	ProdutoID string `json:"produto_id"`
+	// Quantidade de unidades do produto que ficaram de fora.
+	Quantidade int `json:"quantidade"`
+	// Motivo: "muito_grande" (não cabe em nenhuma orientação), "excede_peso" (acima da carga máxima das caixas
+	// em que cabe) ou "orientacao_bloqueada" (só caberia girado além do permitido pela orientação).
+	Motivo   string `json:"motivo" enums:"muito_grande,excede_peso,orientacao_bloqueada"`
+	Mensagem string `json:"mensagem"`
 }
 
 type CaixaResponse struct {
-	CaixaID   string   `json:"caixa_id"`
-	Produtos  []string `json:"produtos"`
+	CaixaID string `json:"caixa_id"`
+	// Produtos lista cada produto do pedido presente na caixa uma vez; Quantidades informa quantas unidades de cada produto_id foram para ela.
+	Produtos    []string       `json:"produtos"`
+	Quantidades map[string]int `json:"quantidades"`
+	// DimensoesExternas e VolumeExterno descrevem a caixa fechada, para etiqueta e cotação de frete.
+	DimensoesExternas DimensoesDTO `json:"dimensoes_externas"`
+	VolumeExterno     int          `json:"volume_externo"`
+	// PesoBruto (kg) soma tara e produtos; omitido quando nenhum peso é conhecido.
+	PesoBruto float64 `json:"peso_bruto,omitempty"`
+	// VolumeCaixa é o volume interno; VolumeUsado soma o espaço ocupado pelos produtos, folga incluída.
+	VolumeCaixa int `json:"volume_caixa"`
+	VolumeUsado int `json:"volume_usado"`
+	// OcupacaoPercentual é VolumeUsado sobre VolumeCaixa, de 0 a 100.
+	OcupacaoPercentual float64 `json:"ocupacao_percentual"`
+	// CentroGravidade do conteúdo, com origem no canto interno da caixa (mesmos eixos de posicao); ponderado pelo peso
+	// dos produtos ou, sem pesos, pelo volume.
+	CentroGravidade PontoDTO `json:"centro_gravidade"`
+	// CustoEstimado só é preenchido com objetivo "custo".
+	CustoEstimado *float64 `json:"custo_estimado,omitempty"`
+	// Posicoes só é preenchido quando o request pede incluir_posicoes.
+	Posicoes []PosicaoProdutoResponse `json:"posicoes,omitempty"`
+	// EspacosLivres só é preenchido quando o request pede incluir_espacos_livres. Com espacos_maximais
+	// os espaços podem se sobrepor, então seus volumes não devem ser somados.
+	EspacosLivres []EspacoLivreResponse `json:"espacos_livres,omitempty"`
+}
+
+// EspacoLivreResponse é um espaço vazio da caixa: origem (canto inferior) e dimensões.
+type EspacoLivreResponse struct {
+	Posicao   CoordenadasDTO `json:"posicao"`
+	Dimensoes DimensoesDTO   `json:"dimensoes"`
+}
+
+// PosicaoProdutoResponse indica onde o produto foi colocado (origem no canto inferior da caixa) e em qual orientação.
+// Posicao e Orientacao descrevem o produto em si; com folga, DimensoesComFolga é o espaço efetivamente reservado,
+// que começa Folga unidades antes da Posicao em cada eixo.
+type PosicaoProdutoResponse struct {
+	ProdutoID         string         `json:"produto_id"`
+	Posicao           CoordenadasDTO `json:"posicao"`
+	Orientacao        DimensoesDTO   `json:"orientacao"`
+	Rotacionado       bool           `json:"rotacionado"`
+	Folga             int            `json:"folga,omitempty"`
+	DimensoesComFolga *DimensoesDTO  `json:"dimensoes_com_folga,omitempty"`
+}
+
+// CoordenadasDTO usa x na largura, y no comprimento e z na altura da caixa.
+type CoordenadasDTO struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+	Z int `json:"z"`
+}
+
+// PontoDTO é uma coordenada não inteira dentro da caixa, nos mesmos eixos de CoordenadasDTO.
+type PontoDTO struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+	Z float64 `json:"z"`
+}
+
+const (
+	JobStatusNaFila      = "na_fila"
+	JobStatusProcessando = "processando"
+	JobStatusConcluido   = "concluido"
+	JobStatusFalhou      = "falhou"
+	JobStatusCancelado   = "cancelado"
+)
+
+// JobResponse descreve um job assíncrono de empacotamento (/v1/packing/jobs).
+type JobResponse struct {
+	JobID string `json:"job_id"`
+	// Status é "na_fila", "processando", "concluido", "falhou" (Erro explica o motivo) ou "cancelado".
+	Status    string               `json:"status" enums:"na_fila,processando,concluido,falhou,cancelado"`
+	Progresso ProgressoJobResponse `json:"progresso"`
+	CriadoEm  time.Time            `json:"criado_em"`
+	// IniciadoEm e FinalizadoEm são preenchidos quando o job sai da fila e quando termina (com qualquer status final).
+	IniciadoEm   *time.Time `json:"iniciado_em,omitempty"`
+	FinalizadoEm *time.Time `json:"finalizado_em,omitempty"`
+	// ExpiraEm é quando um job finalizado e seu resultado deixam de ser consultáveis.
+	ExpiraEm *time.Time `json:"expira_em,omitempty"`
+	// Resultado só é preenchido com status "concluido"; tem o mesmo formato da resposta de POST /v1/packing.
+	Resultado *PackingResponse `json:"resultado,omitempty"`
+	Erro      *ErroResponse    `json:"erro,omitempty"`
+}
+
+// ProgressoJobResponse conta os pedidos do lote já processados.
+type ProgressoJobResponse struct {
+	PedidosProcessados int `json:"pedidos_processados"`
+	TotalPedidos       int `json:"total_pedidos"`
+	// Percentual vai de 0 a 100.
+	Percentual float64 `json:"percentual"`
+}
+
+type CatalogoResponse struct {
+	Caixas []CaixaCatalogoResponse `json:"caixas"`
+}
+
+// CaixaCatalogoResponse representa uma caixa do catálogo; caixas aposentadas (ativa=false) não são usadas no empacotamento.
+type CaixaCatalogoResponse struct {
+	CaixaID   string       `json:"caixa_id"`
+	Dimensoes DimensoesDTO `json:"dimensoes"`
+	// DimensoesExternas já considera a espessura da parede.
+	DimensoesExternas DimensoesDTO `json:"dimensoes_externas"`
+	EspessuraParede   int          `json:"espessura_parede,omitempty"`
+	PesoMaximo        float64      `json:"peso_maximo,omitempty"`
+	Tara              float64      `json:"tara,omitempty"`
+	Custo             float64      `json:"custo,omitempty"`
+	Ativa             bool         `json:"ativa"`
 }
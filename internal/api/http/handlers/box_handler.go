@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/warley004/packing-optimizer-api/internal/api/dto"
+	"github.com/warley004/packing-optimizer-api/internal/catalog"
+	"github.com/warley004/packing-optimizer-api/internal/packing"
+)
+
+type BoxHandler struct {
+	repo catalog.Repository
+}
+
+func NewBoxHandler(repo catalog.Repository) *BoxHandler {
+	return &BoxHandler{repo: repo}
+}
+
+// List godoc
+// @Summary      Listar caixas
+// @Description  Lista o catálogo de caixas. Por padrão apenas caixas ativas; use incluir_aposentadas=true para ver também as aposentadas.
+// @Tags         boxes
+// @Produce      json
+// @Param        incluir_aposentadas  query     bool  false  "Inclui caixas aposentadas"
+// @Success      200                  {object}  dto.CatalogoResponse
+// @Failure      500                  {object}  map[string]any  "Erro interno"
+// @Router       /v1/boxes [get]
+func (h *BoxHandler) List(c *gin.Context) {
+	entries, err := h.repo.List()
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	includeRetired := c.Query("incluir_aposentadas") == "true"
+	resp := dto.CatalogoResponse{Caixas: make([]dto.CaixaCatalogoResponse, 0, len(entries))}
+	for _, e := range entries {
+		if e.Retired && !includeRetired {
+			continue
+		}
+		resp.Caixas = append(resp.Caixas, toCaixaCatalogo(e))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Get godoc
+// @Summary      Consultar caixa
+// @Tags         boxes
+// @Produce      json
+// @Param        id   path      string  true  "ID da caixa"
+// @Success      200  {object}  dto.CaixaCatalogoResponse
+// @Failure      404  {object}  map[string]any  "Caixa não encontrada"
+// @Router       /v1/boxes/{id} [get]
+func (h *BoxHandler) Get(c *gin.Context) {
+	e, err := h.repo.Get(c.Param("id"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toCaixaCatalogo(e))
+}
+
+// Create godoc
+// @Summary      Cadastrar caixa
+// @Description  Adiciona uma caixa ao catálogo; ela passa a ser considerada nos próximos empacotamentos.
+// @Tags         boxes
+// @Accept       json
+// @Produce      json
+// @Param        request  body      dto.CaixaRequest  true  "Caixa a cadastrar"
+// @Success      201      {object}  dto.CaixaCatalogoResponse
+// @Failure      400      {object}  map[string]any  "Erro de validação do JSON/estrutura"
+// @Failure      409      {object}  map[string]any  "caixa_id já cadastrado"
+// @Router       /v1/boxes [post]
+func (h *BoxHandler) Create(c *gin.Context) {
+	var req dto.CaixaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	bt, err := catalog.BoxFromDTO(req.CaixaID, req.CaixaDadosDTO)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+	if err := h.repo.Create(bt); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toCaixaCatalogo(catalog.Entry{BoxType: bt}))
+}
+
+// Update godoc
+// @Summary      Atualizar caixa
+// @Description  Substitui dimensões, peso máximo, tara e custo de uma caixa existente, mantendo seu estado (ativa/aposentada).
+// @Tags         boxes
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                     true  "ID da caixa"
+// @Param        request  body      dto.AtualizarCaixaRequest  true  "Novos dados da caixa"
+// @Success      200      {object}  dto.CaixaCatalogoResponse
+// @Failure      400      {object}  map[string]any  "Erro de validação do JSON/estrutura"
+// @Failure      404      {object}  map[string]any  "Caixa não encontrada"
+// @Router       /v1/boxes/{id} [put]
+func (h *BoxHandler) Update(c *gin.Context) {
+	var req dto.AtualizarCaixaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	bt, err := catalog.BoxFromDTO(c.Param("id"), req.CaixaDadosDTO)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+	if err := h.repo.Update(bt); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	h.Get(c)
+}
+
+// Retire godoc
+// @Summary      Aposentar caixa
+// @Description  Retira a caixa do empacotamento sem apagá-la do catálogo. Não é permitido aposentar a última caixa ativa.
+// @Tags         boxes
+// @Produce      json
+// @Param        id   path      string  true  "ID da caixa"
+// @Success      200  {object}  dto.CaixaCatalogoResponse
+// @Failure      404  {object}  map[string]any  "Caixa não encontrada"
+// @Failure      409  {object}  map[string]any  "Última caixa ativa"
+// @Router       /v1/boxes/{id} [delete]
+func (h *BoxHandler) Retire(c *gin.Context) {
+	if err := h.repo.Retire(c.Param("id")); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	h.Get(c)
+}
+
+// respondError traduz os erros do repositório para status HTTP; erros não mapeados viram 500.
+func (h *BoxHandler) respondError(c *gin.Context, err error) {
+	status, code := http.StatusInternalServerError, "INTERNAL_ERROR"
+	message := "erro interno inesperado"
+
+	switch {
+	case errors.Is(err, catalog.ErrNotFound):
+		status, code, message = http.StatusNotFound, "BOX_NOT_FOUND", err.Error()
+	case errors.Is(err, catalog.ErrAlreadyExists):
+		status, code, message = http.StatusConflict, "BOX_ALREADY_EXISTS", err.Error()
+	case errors.Is(err, catalog.ErrLastActiveBox):
+		status, code, message = http.StatusConflict, "LAST_ACTIVE_BOX", err.Error()
+	case errors.Is(err, catalog.ErrInvalidBox):
+		status, code, message = http.StatusBadRequest, "VALIDATION_ERROR", err.Error()
+	}
+
+	c.JSON(status, gin.H{
+		"error": gin.H{
+			"code":    code,
+			"message": message,
+		},
+	})
+}
+
+func respondValidationError(c *gin.Context, err error) {
+	c.JSON(http.StatusBadRequest, gin.H{
+		"error": gin.H{
+			"code":    "VALIDATION_ERROR",
+			"message": err.Error(),
+		},
+	})
+}
+
+func toCaixaCatalogo(e catalog.Entry) dto.CaixaCatalogoResponse {
+	return dto.CaixaCatalogoResponse{
+		CaixaID: e.BoxType.ID,
+		Dimensoes: dto.DimensoesDTO{
+			Altura:      e.BoxType.Height,
+			Largura:     e.BoxType.Width,
+			Comprimento: e.BoxType.Length,
+		},
+		DimensoesExternas: toDimensoes(e.BoxType.OuterDimensions()),
+		EspessuraParede:   e.BoxType.WallThickness,
+		PesoMaximo:        e.BoxType.MaxWeight,
+		Tara:              e.BoxType.TareWeight,
+		Custo:             e.BoxType.UnitCost,
+		Ativa:             !e.Retired,
+	}
+}
+
+func toDimensoes(d packing.Dimensions) dto.DimensoesDTO {
+	return dto.DimensoesDTO{Altura: d.Height, Largura: d.Width, Comprimento: d.Length}
+}
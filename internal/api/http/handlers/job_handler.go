@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/warley004/packing-optimizer-api/internal/api/dto"
+	"github.com/warley004/packing-optimizer-api/internal/service"
+)
+
+type JobHandler struct {
+	jobs *service.JobManager
+}
+
+func NewJobHandler(jobs *service.JobManager) *JobHandler {
+	return &JobHandler{jobs: jobs}
+}
+
+// Submit godoc
+// @Summary      Criar job de empacotamento
+// @Description  Enfileira um lote (mesmo formato de POST /v1/packing) para processamento assíncrono; acompanhe o progresso e obtenha o resultado em GET /v1/packing/jobs/{id}.
+// @Tags         packing
+// @Accept       json
+// @Produce      json
+// @Param        request  body      dto.PackingRequest  true  "Lista de pedidos com produtos e dimensões"
+// @Success      202      {object}  dto.JobResponse
+// @Failure      400      {object}  map[string]any  "Erro de validação do JSON/estrutura"
+// @Failure      503      {object}  map[string]any  "Fila de jobs cheia"
+// @Router       /v1/packing/jobs [post]
+func (h *JobHandler) Submit(c *gin.Context) {
+	var req dto.PackingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	job, err := h.jobs.Submit(req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	c.Header("Location", "/v1/packing/jobs/"+job.JobID)
+	c.JSON(http.StatusAccepted, job)
+}
+
+// Get godoc
+// @Summary      Consultar job de empacotamento
+// @Description  Devolve status e progresso do job; com status "concluido", inclui o resultado do lote. Jobs finalizados ficam disponíveis até expira_em.
+// @Tags         packing
+// @Produce      json
+// @Param        id   path      string  true  "ID do job"
+// @Success      200  {object}  dto.JobResponse
+// @Failure      404  {object}  map[string]any  "Job não encontrado ou expirado"
+// @Router       /v1/packing/jobs/{id} [get]
+func (h *JobHandler) Get(c *gin.Context) {
+	job, err := h.jobs.Get(c.Param("id"))
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// Cancel godoc
+// @Summary      Cancelar job de empacotamento
+// @Description  Cancela um job na fila ou em processamento; pedidos já iniciados terminam, mas o lote não gera resultado.
+// @Tags         packing
+// @Produce      json
+// @Param        id   path      string  true  "ID do job"
+// @Success      200  {object}  dto.JobResponse
+// @Failure      404  {object}  map[string]any  "Job não encontrado ou expirado"
+// @Failure      409  {object}  map[string]any  "Job já finalizado"
+// @Router       /v1/packing/jobs/{id} [delete]
+func (h *JobHandler) Cancel(c *gin.Context) {
+	job, err := h.jobs.Cancel(c.Param("id"))
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
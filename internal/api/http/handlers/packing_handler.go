@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"context"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -13,10 +15,10 @@ type PackingHandler struct {
 	service *service.PackingService
 }
 
-func NewPackingHandler() *PackingHandler {
+func NewPackingHandler(svc *service.PackingService) *PackingHandler {
 	// Handler orquestra entrada HTTP e delega regra de negócio para o service.
 	return &PackingHandler{
-		service: service.NewPackingService(),
+		service: svc,
 	}
 }
 
@@ -28,46 +30,60 @@ func NewPackingHandler() *PackingHandler {
 // @Produce      json
 // @Param        request  body      dto.PackingRequest  true  "Lista de pedidos com produtos e dimensões"
 // @Success      200      {object}  dto.PackingResponse
-// @Failure      400      {object}  map[string]any  "Erro de validação do JSON/estrutura"
-// @Failure      422      {object}  map[string]any  "Erro de empacotamento (produto não cabe)"
+// @Success      207      {object}  dto.PackingResponse  "Modo melhor_esforco com pedidos que não puderam ser empacotados (status erro)"
+// @Failure      400      {object}  map[string]any  "Erro de validação do JSON/estrutura (inclui caixas inline inválidas)"
+// @Failure      422      {object}  map[string]any  "Erro de empacotamento (produto não cabe) ou caixas_permitidas desconhecidas, no modo falhar_rapido"
 // @Failure      500      {object}  map[string]any  "Erro interno"
+// @Failure      503      {object}  map[string]any  "Pool de empacotamento saturado (POOL_SATURATED)"
+// @Failure      504      {object}  map[string]any  "Empacotamento excedeu o tempo limite do servidor (PACKING_TIMEOUT)"
 // @Router       /v1/packing [post]
 func (h *PackingHandler) Pack(c *gin.Context) {
 	// Validação sintática/JSON ocorre no handler para responder 400 sem invocar o domínio.
 	var req dto.PackingRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":    "VALIDATION_ERROR",
-				"message": err.Error(),
-			},
-		})
+		respondValidationError(c, err)
 		return
 	}
 
-	resp, err := h.service.Pack(req)
+	resp, err := h.service.Pack(c.Request.Context(), req)
 	if err != nil {
-		if se, ok := err.(*service.ServiceError); ok {
-			// ServiceError já traz o status apropriado definido pelas regras de negócio.
-			c.JSON(se.StatusCode, gin.H{
-				"error": gin.H{
-					"code":    "PACKING_ERROR",
-					"message": se.Message,
-				},
-			})
-			return
-		}
+		respondServiceError(c, err)
+		return
+	}
 
-		// Fallback 500 para falhas inesperadas não mapeadas pelo service.
-		c.JSON(http.StatusInternalServerError, gin.H{
+	// Service já garante preservação da ordem dos produtos; handler apenas serializa o DTO final.
+	// No modo "melhor_esforco", 207 sinaliza que parte dos pedidos traz status "erro".
+	status := http.StatusOK
+	if resp.PedidosComErro > 0 {
+		status = http.StatusMultiStatus
+	}
+	c.JSON(status, resp)
+}
+
+// respondServiceError escreve o erro do service no formato padrão da API.
+func respondServiceError(c *gin.Context, err error) {
+	if se, ok := err.(*service.ServiceError); ok {
+		// ServiceError já traz o status apropriado definido pelas regras de negócio.
+		c.JSON(se.StatusCode, gin.H{
 			"error": gin.H{
-				"code":    "INTERNAL_ERROR",
-				"message": "erro interno inesperado",
+				"code":    se.ErrorCode(),
+				"message": se.Message,
 			},
 		})
 		return
 	}
 
-	// Service já garante preservação da ordem dos produtos; handler apenas serializa o DTO final.
-	c.JSON(http.StatusOK, resp)
+	if errors.Is(err, context.Canceled) {
+		// O cliente desconectou: não há a quem responder, só encerra sem registrar como erro interno.
+		c.Abort()
+		return
+	}
+
+	// Fallback 500 para falhas inesperadas não mapeadas pelo service.
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error": gin.H{
+			"code":    "INTERNAL_ERROR",
+			"message": "erro interno inesperado",
+		},
+	})
 }
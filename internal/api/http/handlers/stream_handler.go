@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+
+	"github.com/warley004/packing-optimizer-api/internal/api/dto"
+	"github.com/warley004/packing-optimizer-api/internal/service"
+)
+
+// PackStream godoc
+// @Summary      Empacotar pedidos em streaming (NDJSON)
+// @Description  Recebe um PedidoRequest por linha (application/x-ndjson) e devolve um PedidoResponse por linha assim que cada pedido é empacotado, na ordem de conclusão (use pedido_id para correlacionar). Pedidos inválidos ou que não cabem voltam com status "erro" e o fluxo continua; um JSON malformado encerra o fluxo com uma última linha {"error": {...}}. As opções de lote vão na query string.
+// @Tags         packing
+// @Accept       application/x-ndjson
+// @Produce      application/x-ndjson
+// @Param        request                 body      dto.PedidoRequest  true   "Um pedido por linha"
+// @Param        algoritmo               query     string             false  "ffd, bfd, camadas ou melhor"
+// @Param        estrategia_espacos      query     string             false  "guilhotina ou espacos_maximais"
+// @Param        objetivo                query     string             false  "caixas ou custo"
+// @Param        permitir_parcial        query     bool               false  "Empacota o que couber"
+// @Param        folga_padrao            query     int                false  "Folga padrão dos produtos"
+// @Param        apoio_minimo            query     number             false  "Percentual mínimo da base apoiada (0 a 100)"
+// @Param        balancear               query     bool               false  "Desempata posições pelo centro de gravidade"
+// @Param        incluir_posicoes        query     bool               false  "Inclui posições dos produtos"
+// @Param        incluir_espacos_livres  query     bool               false  "Inclui espaços livres das caixas"
+// @Param        caixas_permitidas       query     []string           false  "Restringe as caixas do catálogo" collectionFormat(multi)
+// @Param        divisor_peso_cubado     query     number             false  "modelo_custo: divisor do peso cubado"
+// @Param        valor_por_kg            query     number             false  "modelo_custo: frete por kg tarifável"
+// @Param        custo_preenchimento     query     number             false  "modelo_custo: preenchimento por volume vazio"
+// @Success      200                     {object}  dto.PedidoResponse  "Uma linha por pedido"
+// @Failure      400                     {object}  map[string]any  "Query inválida ou primeira linha malformada"
+// @Failure      422                     {object}  map[string]any  "caixas_permitidas desconhecidas"
+// @Failure      503                     {object}  map[string]any  "Pool de empacotamento saturado (POOL_SATURATED)"
+// @Router       /v1/packing/stream [post]
+func (h *PackingHandler) PackStream(c *gin.Context) {
+	var q dto.PackingStreamQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		respondValidationError(c, err)
+		return
+	}
+
+	// Sem full duplex, o servidor HTTP/1 leria o corpo inteiro antes da primeira resposta.
+	_ = http.NewResponseController(c.Writer).EnableFullDuplex()
+
+	dec := json.NewDecoder(c.Request.Body)
+	next := func() (dto.PedidoRequest, error) {
+		var pedido dto.PedidoRequest
+		if err := dec.Decode(&pedido); err != nil {
+			return dto.PedidoRequest{}, err
+		}
+		if err := binding.Validator.ValidateStruct(&pedido); err != nil {
+			return pedido, &service.ServiceError{
+				StatusCode: http.StatusBadRequest,
+				Code:       "VALIDATION_ERROR",
+				Message:    err.Error(),
+			}
+		}
+		return pedido, nil
+	}
+
+	enc := json.NewEncoder(c.Writer)
+	var writeErr error
+	emit := func(pr dto.PedidoResponse) error {
+		if !c.Writer.Written() {
+			c.Header("Content-Type", "application/x-ndjson")
+			c.Status(http.StatusOK)
+		}
+		if writeErr = enc.Encode(pr); writeErr != nil {
+			return writeErr
+		}
+		c.Writer.Flush()
+		return nil
+	}
+
+	err := h.service.PackStream(c.Request.Context(), streamRequest(q), next, emit)
+	if err == nil || writeErr != nil || c.Request.Context().Err() != nil {
+		// Cliente desconectado: não há a quem responder.
+		return
+	}
+
+	var se *service.ServiceError
+	if !errors.As(err, &se) {
+		// Erro de leitura: JSON malformado ou corpo interrompido.
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			err = errors.New("corpo NDJSON interrompido no meio de um pedido")
+		}
+		se = &service.ServiceError{StatusCode: http.StatusBadRequest, Code: "VALIDATION_ERROR", Message: err.Error()}
+	}
+	if !c.Writer.Written() {
+		respondServiceError(c, se)
+		return
+	}
+	// O status 200 já foi enviado: o erro vai como última linha do fluxo.
+	_ = enc.Encode(gin.H{"error": gin.H{"code": se.ErrorCode(), "message": se.Message}})
+	c.Writer.Flush()
+}
+
+func streamRequest(q dto.PackingStreamQuery) dto.PackingRequest {
+	return dto.PackingRequest{
+		PermitirParcial:      q.PermitirParcial,
+		FolgaPadrao:          q.FolgaPadrao,
+		ApoioMinimo:          q.ApoioMinimo,
+		Balancear:            q.Balancear,
+		IncluirPosicoes:      q.IncluirPosicoes,
+		IncluirEspacosLivres: q.IncluirEspacosLivres,
+		EstrategiaEspacos:    q.EstrategiaEspacos,
+		Algoritmo:            q.Algoritmo,
+		Objetivo:             q.Objetivo,
+		CaixasPermitidas:     q.CaixasPermitidas,
+		// Parâmetros zerados equivalem a modelo_custo ausente (só o custo das caixas).
+		ModeloCusto: &dto.ModeloCustoDTO{
+			DivisorPesoCubado:  q.DivisorPesoCubado,
+			ValorPorKg:         q.ValorPorKg,
+			CustoPreenchimento: q.CustoPreenchimento,
+		},
+	}
+}
@@ -9,19 +9,38 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 
 	"github.com/warley004/packing-optimizer-api/internal/api/http/handlers"
+	"github.com/warley004/packing-optimizer-api/internal/catalog"
+	"github.com/warley004/packing-optimizer-api/internal/metrics"
+	"github.com/warley004/packing-optimizer-api/internal/service"
 )
 
-func RegisterRoutes(r *gin.Engine) {
+func RegisterRoutes(r *gin.Engine, boxes catalog.Repository, packingService *service.PackingService, jobs *service.JobManager, m *metrics.Metrics) {
 	r.GET("/healthz", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"status": "ok"})
 	})
 
+	// Métricas no formato Prometheus; o middleware de coleta é instalado em main, antes das rotas.
+	r.GET("/metrics", gin.WrapH(m.Handler()))
+
 	// Swagger UI
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	v1 := r.Group("/v1")
 	{
-		packingHandler := handlers.NewPackingHandler()
+		packingHandler := handlers.NewPackingHandler(packingService)
 		v1.POST("/packing", packingHandler.Pack)
+		v1.POST("/packing/stream", packingHandler.PackStream)
+
+		jobHandler := handlers.NewJobHandler(jobs)
+		v1.POST("/packing/jobs", jobHandler.Submit)
+		v1.GET("/packing/jobs/:id", jobHandler.Get)
+		v1.DELETE("/packing/jobs/:id", jobHandler.Cancel)
+
+		boxHandler := handlers.NewBoxHandler(boxes)
+		v1.GET("/boxes", boxHandler.List)
+		v1.POST("/boxes", boxHandler.Create)
+		v1.GET("/boxes/:id", boxHandler.Get)
+		v1.PUT("/boxes/:id", boxHandler.Update)
+		v1.DELETE("/boxes/:id", boxHandler.Retire)
 	}
 }
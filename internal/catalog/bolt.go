@@ -0,0 +1,200 @@
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/warley004/packing-optimizer-api/internal/packing"
+)
+
+var boxesBucket = []byte("boxes")
+
+// BoltRepository persiste o catálogo em um arquivo BoltDB embarcado (sem servidor externo).
+type BoltRepository struct {
+	db *bolt.DB
+}
+
+// boltRecord é o formato gravado em disco; desacoplado de packing.BoxType para que o domínio possa evoluir sem migrar o arquivo.
+type boltRecord struct {
+	ID     string `json:"id"`
+	Height int    `json:"height"`
+	Width  int    `json:"width"`
+	Length int    `json:"length"`
+	// Campos opcionais: registros gravados antes deles existirem continuam válidos (sem limite, sem tara, sem custo).
+	MaxWeight  float64 `json:"max_weight,omitempty"`
+	TareWeight float64 `json:"tare_weight,omitempty"`
+	UnitCost   float64 `json:"unit_cost,omitempty"`
+	// Dimensões externas explícitas e espessura da parede; ausentes significam externas iguais às internas.
+	WallThickness int  `json:"wall_thickness,omitempty"`
+	OuterHeight   int  `json:"outer_height,omitempty"`
+	OuterWidth    int  `json:"outer_width,omitempty"`
+	OuterLength   int  `json:"outer_length,omitempty"`
+	Retired       bool `json:"retired"`
+}
+
+func NewBoltRepository(path string) (*BoltRepository, error) {
+	// Timeout evita travar a subida indefinidamente se outro processo mantiver o arquivo aberto.
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("falha ao abrir catálogo em disco '%s': %w", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(boxesBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("falha ao inicializar catálogo em disco '%s': %w", path, err)
+	}
+
+	return &BoltRepository{db: db}, nil
+}
+
+func (r *BoltRepository) List() ([]Entry, error) {
+	var entries []Entry
+	err := r.db.View(func(tx *bolt.Tx) error {
+		var err error
+		entries, err = readAll(tx.Bucket(boxesBucket))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	sortEntries(entries)
+	return entries, nil
+}
+
+func (r *BoltRepository) Get(id string) (Entry, error) {
+	var e Entry
+	err := r.db.View(func(tx *bolt.Tx) error {
+		var err error
+		e, err = get(tx.Bucket(boxesBucket), id)
+		return err
+	})
+	return e, err
+}
+
+func (r *BoltRepository) Create(bt packing.BoxType) error {
+	if err := ValidateBox(bt); err != nil {
+		return err
+	}
+
+	return r.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(boxesBucket)
+		if b.Get([]byte(bt.ID)) != nil {
+			return ErrAlreadyExists
+		}
+		return put(b, Entry{BoxType: bt})
+	})
+}
+
+func (r *BoltRepository) Update(bt packing.BoxType) error {
+	if err := ValidateBox(bt); err != nil {
+		return err
+	}
+
+	return r.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(boxesBucket)
+		e, err := get(b, bt.ID)
+		if err != nil {
+			return err
+		}
+		e.BoxType = bt
+		return put(b, e)
+	})
+}
+
+func (r *BoltRepository) Retire(id string) error {
+	return r.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(boxesBucket)
+		e, err := get(b, id)
+		if err != nil {
+			return err
+		}
+		if e.Retired {
+			return nil
+		}
+
+		entries, err := readAll(b)
+		if err != nil {
+			return err
+		}
+		if countActive(entries) == 1 {
+			return ErrLastActiveBox
+		}
+
+		e.Retired = true
+		return put(b, e)
+	})
+}
+
+func (r *BoltRepository) Close() error {
+	return r.db.Close()
+}
+
+func get(b *bolt.Bucket, id string) (Entry, error) {
+	v := b.Get([]byte(id))
+	if v == nil {
+		return Entry{}, ErrNotFound
+	}
+	return decodeEntry(v)
+}
+
+func put(b *bolt.Bucket, e Entry) error {
+	v, err := json.Marshal(boltRecord{
+		ID:            e.BoxType.ID,
+		Height:        e.BoxType.Height,
+		Width:         e.BoxType.Width,
+		Length:        e.BoxType.Length,
+		MaxWeight:     e.BoxType.MaxWeight,
+		TareWeight:    e.BoxType.TareWeight,
+		UnitCost:      e.BoxType.UnitCost,
+		WallThickness: e.BoxType.WallThickness,
+		OuterHeight:   e.BoxType.Outer.Height,
+		OuterWidth:    e.BoxType.Outer.Width,
+		OuterLength:   e.BoxType.Outer.Length,
+		Retired:       e.Retired,
+	})
+	if err != nil {
+		return err
+	}
+	return b.Put([]byte(e.BoxType.ID), v)
+}
+
+func readAll(b *bolt.Bucket) ([]Entry, error) {
+	var entries []Entry
+	err := b.ForEach(func(_, v []byte) error {
+		e, err := decodeEntry(v)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, e)
+		return nil
+	})
+	return entries, err
+}
+
+func decodeEntry(v []byte) (Entry, error) {
+	var rec boltRecord
+	if err := json.Unmarshal(v, &rec); err != nil {
+		return Entry{}, fmt.Errorf("registro de caixa corrompido: %w", err)
+	}
+	return Entry{
+		BoxType: packing.BoxType{
+			ID:            rec.ID,
+			Height:        rec.Height,
+			Width:         rec.Width,
+			Length:        rec.Length,
+			WallThickness: rec.WallThickness,
+			Outer:         packing.Dimensions{Height: rec.OuterHeight, Width: rec.OuterWidth, Length: rec.OuterLength},
+			MaxWeight:     rec.MaxWeight,
+			TareWeight:    rec.TareWeight,
+			UnitCost:      rec.UnitCost,
+		},
+		Retired: rec.Retired,
+	}, nil
+}
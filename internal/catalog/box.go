@@ -0,0 +1,46 @@
+package catalog
+
+import (
+	"github.com/warley004/packing-optimizer-api/internal/api/dto"
+	"github.com/warley004/packing-optimizer-api/internal/packing"
+)
+
+// BoxFromDTO converte os dados de uma caixa no formato da API e valida o resultado (ver ValidateBox).
+// É a conversão usada pelo cadastro REST; o arquivo de catálogo e as caixas inline usam BoxesFromDTO,
+// então todas as entradas aceitam e recusam as mesmas caixas.
+func BoxFromDTO(id string, d dto.CaixaDadosDTO) (packing.BoxType, error) {
+	bt := toBoxType(id, d)
+	if err := ValidateBox(bt); err != nil {
+		return packing.BoxType{}, err
+	}
+	return bt, nil
+}
+
+// BoxesFromDTO converte uma lista de caixas no formato da API e valida o conjunto (ver Validate).
+func BoxesFromDTO(caixas []dto.CaixaRequest) ([]packing.BoxType, error) {
+	boxes := make([]packing.BoxType, 0, len(caixas))
+	for _, c := range caixas {
+		boxes = append(boxes, toBoxType(c.CaixaID, c.CaixaDadosDTO))
+	}
+	if err := Validate(boxes); err != nil {
+		return nil, err
+	}
+	return boxes, nil
+}
+
+func toBoxType(id string, d dto.CaixaDadosDTO) packing.BoxType {
+	bt := packing.BoxType{
+		ID:            id,
+		Height:        d.Dimensoes.Altura,
+		Width:         d.Dimensoes.Largura,
+		Length:        d.Dimensoes.Comprimento,
+		WallThickness: d.EspessuraParede,
+		MaxWeight:     d.PesoMaximo,
+		TareWeight:    d.Tara,
+		UnitCost:      d.Custo,
+	}
+	if e := d.DimensoesExternas; e != nil {
+		bt.Outer = packing.Dimensions{Height: e.Altura, Width: e.Largura, Length: e.Comprimento}
+	}
+	return bt
+}
@@ -0,0 +1,44 @@
+package catalog
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/warley004/packing-optimizer-api/internal/api/dto"
+	"github.com/warley004/packing-optimizer-api/internal/packing"
+)
+
+func TestBoxFromDTO(t *testing.T) {
+	bt, err := BoxFromDTO("Caixa", dto.CaixaDadosDTO{
+		Dimensoes:         dto.DimensoesDTO{Altura: 10, Largura: 20, Comprimento: 30},
+		DimensoesExternas: &dto.DimensoesDTO{Altura: 12, Largura: 22, Comprimento: 32},
+		PesoMaximo:        5,
+		Tara:              0.5,
+		Custo:             2,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := packing.BoxType{
+		ID: "Caixa", Height: 10, Width: 20, Length: 30,
+		Outer:     packing.Dimensions{Height: 12, Width: 22, Length: 32},
+		MaxWeight: 5, TareWeight: 0.5, UnitCost: 2,
+	}
+	if bt != want {
+		t.Fatalf("expected %+v, got %+v", want, bt)
+	}
+}
+
+func TestBoxFromDTO_RejectsTheSameBoxesAsTheFileLoader(t *testing.T) {
+	invalid := dto.CaixaDadosDTO{
+		Dimensoes:         dto.DimensoesDTO{Altura: 10, Largura: 10, Comprimento: 10},
+		DimensoesExternas: &dto.DimensoesDTO{Altura: 12, Largura: 9, Comprimento: 12},
+	}
+
+	if _, err := BoxFromDTO("A", invalid); !errors.Is(err, ErrInvalidBox) {
+		t.Fatalf("expected ErrInvalidBox, got %v", err)
+	}
+	if _, err := BoxesFromDTO([]dto.CaixaRequest{{CaixaID: "A", CaixaDadosDTO: invalid}}); !errors.Is(err, ErrInvalidBox) {
+		t.Fatalf("expected ErrInvalidBox for the list, got %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+package catalog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/warley004/packing-optimizer-api/internal/api/dto"
+	"github.com/warley004/packing-optimizer-api/internal/packing"
+)
+
+// fileCatalog é o formato do arquivo de catálogo: as mesmas caixas aceitas pela API (caixa_id, dimensoes...).
+type fileCatalog struct {
+	Caixas []dto.CaixaRequest `json:"caixas" yaml:"caixas"`
+}
+
+// LoadFile lê e valida o catálogo de caixas de um arquivo JSON (.json) ou YAML (.yaml/.yml).
+// Campos desconhecidos são rejeitados para que erros de digitação não passem despercebidos.
+func LoadFile(path string) ([]packing.BoxType, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao ler catálogo de caixas '%s': %w", path, err)
+	}
+
+	var fc fileCatalog
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&fc)
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		err = dec.Decode(&fc)
+	default:
+		return nil, fmt.Errorf("catálogo de caixas '%s': extensão '%s' não suportada (use .json, .yaml ou .yml)", path, ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("catálogo de caixas '%s' malformado: %w", path, err)
+	}
+
+	boxes, err := BoxesFromDTO(fc.Caixas)
+	if err != nil {
+		return nil, fmt.Errorf("catálogo de caixas '%s' inválido: %w", path, err)
+	}
+
+	return boxes, nil
+}
+
+// Validate garante que o catálogo é utilizável: ao menos uma caixa, IDs únicos e cada caixa válida (ver ValidateBox).
+func Validate(boxes []packing.BoxType) error {
+	if len(boxes) == 0 {
+		return fmt.Errorf("nenhuma caixa definida")
+	}
+
+	seen := make(map[string]bool, len(boxes))
+	for i, b := range boxes {
+		if strings.TrimSpace(b.ID) == "" {
+			return fmt.Errorf("%w: caixa #%d sem caixa_id", ErrInvalidBox, i+1)
+		}
+		if seen[b.ID] {
+			return fmt.Errorf("caixa_id '%s' duplicado", b.ID)
+		}
+		seen[b.ID] = true
+
+		if err := ValidateBox(b); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ValidateBox verifica uma caixa isolada: ID preenchido, dimensões positivas, dimensões externas que comportam
+// as internas e pesos/custo não negativos.
+func ValidateBox(b packing.BoxType) error {
+	if strings.TrimSpace(b.ID) == "" {
+		return fmt.Errorf("%w: caixa_id vazio", ErrInvalidBox)
+	}
+	if b.Height <= 0 || b.Width <= 0 || b.Length <= 0 {
+		return fmt.Errorf("%w: caixa '%s' com dimensões inválidas (%dx%dx%d): todas devem ser maiores que zero", ErrInvalidBox, b.ID, b.Height, b.Width, b.Length)
+	}
+	if b.WallThickness < 0 {
+		return fmt.Errorf("%w: caixa '%s' com espessura_parede negativa", ErrInvalidBox, b.ID)
+	}
+	if b.Outer != (packing.Dimensions{}) && !b.InnerDimensions().FitsIn(b.Outer) {
+		o := b.Outer
+		return fmt.Errorf("%w: caixa '%s' com dimensões externas (%dx%dx%d) menores que as internas (%dx%dx%d)", ErrInvalidBox, b.ID, o.Height, o.Width, o.Length, b.Height, b.Width, b.Length)
+	}
+	if b.MaxWeight < 0 || b.TareWeight < 0 {
+		return fmt.Errorf("%w: caixa '%s' com peso_maximo/tara negativos", ErrInvalidBox, b.ID)
+	}
+	if b.UnitCost < 0 {
+		return fmt.Errorf("%w: caixa '%s' com custo negativo", ErrInvalidBox, b.ID)
+	}
+	return nil
+}
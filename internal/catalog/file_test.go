@@ -0,0 +1,105 @@
+package catalog
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/warley004/packing-optimizer-api/internal/packing"
+)
+
+func writeCatalog(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write catalog: %v", err)
+	}
+	return path
+}
+
+func TestLoadFile_YAML(t *testing.T) {
+	path := writeCatalog(t, "boxes.yaml", `
+caixas:
+  - caixa_id: P
+    dimensoes: { altura: 10, largura: 20, comprimento: 30 }
+  - caixa_id: G
+    dimensoes: { altura: 50, largura: 60, comprimento: 70 }
+`)
+
+	boxes, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(boxes) != 2 || boxes[0].ID != "P" || boxes[1].Length != 70 {
+		t.Fatalf("unexpected boxes: %+v", boxes)
+	}
+}
+
+func TestLoadFile_JSON(t *testing.T) {
+	path := writeCatalog(t, "boxes.json", `{"caixas":[{"caixa_id":"Unica","dimensoes":{"altura":1,"largura":2,"comprimento":3}}]}`)
+
+	boxes, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(boxes) != 1 || boxes[0].Width != 2 {
+		t.Fatalf("unexpected boxes: %+v", boxes)
+	}
+}
+
+func TestLoadFile_OuterDimensions(t *testing.T) {
+	path := writeCatalog(t, "boxes.yaml", `
+caixas:
+  - caixa_id: Parede
+    dimensoes: { altura: 10, largura: 20, comprimento: 30 }
+    espessura_parede: 1
+  - caixa_id: Medida
+    dimensoes: { altura: 10, largura: 20, comprimento: 30 }
+    dimensoes_externas: { altura: 11, largura: 23, comprimento: 31 }
+`)
+
+	boxes, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := boxes[0].OuterDimensions(); got != (packing.Dimensions{Height: 12, Width: 22, Length: 32}) {
+		t.Fatalf("expected outer dimensions from wall thickness, got %+v", got)
+	}
+	if got := boxes[1].OuterDimensions(); got != (packing.Dimensions{Height: 11, Width: 23, Length: 31}) {
+		t.Fatalf("expected measured outer dimensions, got %+v", got)
+	}
+	if got := boxes[1].InnerDimensions(); got != (packing.Dimensions{Height: 10, Width: 20, Length: 30}) {
+		t.Fatalf("expected inner dimensions unchanged, got %+v", got)
+	}
+}
+
+func TestLoadFile_RejectsInvalidCatalogs(t *testing.T) {
+	cases := map[string]struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		"duplicated id": {"boxes.json", `{"caixas":[
+			{"caixa_id":"A","dimensoes":{"altura":1,"largura":1,"comprimento":1}},
+			{"caixa_id":"A","dimensoes":{"altura":2,"largura":2,"comprimento":2}}]}`, "duplicado"},
+		"zero dimension": {"boxes.json", `{"caixas":[{"caixa_id":"A","dimensoes":{"altura":0,"largura":1,"comprimento":1}}]}`, "dimensões inválidas"},
+		"outer smaller": {"boxes.json", `{"caixas":[{"caixa_id":"A","dimensoes":{"altura":10,"largura":10,"comprimento":10},
+			"dimensoes_externas":{"altura":12,"largura":9,"comprimento":12}}]}`, "menores que as internas"},
+		"empty":         {"boxes.yaml", `caixas: []`, "nenhuma caixa"},
+		"unknown field": {"boxes.yaml", "caixas:\n  - caixa_id: A\n    altura: 1\n", "malformado"},
+		"extension":     {"boxes.txt", `caixas: []`, "não suportada"},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := LoadFile(writeCatalog(t, tc.name, tc.content))
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
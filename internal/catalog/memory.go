@@ -0,0 +1,101 @@
+package catalog
+
+import (
+	"sync"
+
+	"github.com/warley004/packing-optimizer-api/internal/packing"
+)
+
+// MemoryRepository mantém o catálogo apenas em memória; alterações se perdem ao reiniciar a API.
+type MemoryRepository struct {
+	mu      sync.RWMutex
+	entries map[string]Entry
+}
+
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{entries: make(map[string]Entry)}
+}
+
+func (r *MemoryRepository) List() ([]Entry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries := make([]Entry, 0, len(r.entries))
+	for _, e := range r.entries {
+		entries = append(entries, e)
+	}
+	sortEntries(entries)
+	return entries, nil
+}
+
+func (r *MemoryRepository) Get(id string) (Entry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	e, ok := r.entries[id]
+	if !ok {
+		return Entry{}, ErrNotFound
+	}
+	return e, nil
+}
+
+func (r *MemoryRepository) Create(bt packing.BoxType) error {
+	if err := ValidateBox(bt); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.entries[bt.ID]; ok {
+		return ErrAlreadyExists
+	}
+	r.entries[bt.ID] = Entry{BoxType: bt}
+	return nil
+}
+
+func (r *MemoryRepository) Update(bt packing.BoxType) error {
+	if err := ValidateBox(bt); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, ok := r.entries[bt.ID]
+	if !ok {
+		return ErrNotFound
+	}
+	e.BoxType = bt
+	r.entries[bt.ID] = e
+	return nil
+}
+
+func (r *MemoryRepository) Retire(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, ok := r.entries[id]
+	if !ok {
+		return ErrNotFound
+	}
+	if e.Retired {
+		return nil
+	}
+
+	entries := make([]Entry, 0, len(r.entries))
+	for _, o := range r.entries {
+		entries = append(entries, o)
+	}
+	if countActive(entries) == 1 {
+		return ErrLastActiveBox
+	}
+
+	e.Retired = true
+	r.entries[id] = e
+	return nil
+}
+
+func (r *MemoryRepository) Close() error {
+	return nil
+}
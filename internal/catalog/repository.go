@@ -0,0 +1,87 @@
+package catalog
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/warley004/packing-optimizer-api/internal/packing"
+)
+
+var (
+	ErrNotFound      = errors.New("caixa não encontrada")
+	ErrAlreadyExists = errors.New("caixa já existe")
+	ErrInvalidBox    = errors.New("caixa inválida")
+	// ErrLastActiveBox impede aposentar a última caixa ativa, o que deixaria o empacotamento sem recipientes.
+	ErrLastActiveBox = errors.New("não é possível aposentar a última caixa ativa do catálogo")
+)
+
+// Entry é uma caixa do catálogo. Caixas aposentadas continuam registradas (histórico), mas não são usadas no empacotamento.
+type Entry struct {
+	BoxType packing.BoxType
+	Retired bool
+}
+
+// Repository armazena o catálogo de caixas. Implementações devem ser seguras para uso concorrente.
+type Repository interface {
+	// List devolve todas as caixas (ativas e aposentadas) ordenadas por ID.
+	List() ([]Entry, error)
+	Get(id string) (Entry, error)
+	// Create falha com ErrAlreadyExists se o ID já estiver cadastrado, inclusive por uma caixa aposentada.
+	Create(bt packing.BoxType) error
+	// Update substitui as dimensões de uma caixa existente mantendo seu estado (ativa/aposentada).
+	Update(bt packing.BoxType) error
+	Retire(id string) error
+	Close() error
+}
+
+// ActiveBoxes devolve o catálogo utilizável pelo empacotamento (somente caixas não aposentadas).
+func ActiveBoxes(repo Repository) ([]packing.BoxType, error) {
+	entries, err := repo.List()
+	if err != nil {
+		return nil, err
+	}
+
+	boxes := make([]packing.BoxType, 0, len(entries))
+	for _, e := range entries {
+		if !e.Retired {
+			boxes = append(boxes, e.BoxType)
+		}
+	}
+	return boxes, nil
+}
+
+// Seed popula um repositório vazio; repositórios já populados (ex.: arquivo em disco de execuções anteriores) são preservados.
+// Retorna true quando as caixas foram gravadas.
+func Seed(repo Repository, boxes []packing.BoxType) (bool, error) {
+	entries, err := repo.List()
+	if err != nil {
+		return false, err
+	}
+	if len(entries) > 0 {
+		return false, nil
+	}
+
+	for _, bt := range boxes {
+		if err := repo.Create(bt); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func sortEntries(entries []Entry) {
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].BoxType.ID < entries[j].BoxType.ID
+	})
+}
+
+// countActive é usado pelas implementações para aplicar ErrLastActiveBox.
+func countActive(entries []Entry) int {
+	n := 0
+	for _, e := range entries {
+		if !e.Retired {
+			n++
+		}
+	}
+	return n
+}
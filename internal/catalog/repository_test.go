@@ -0,0 +1,75 @@
+package catalog
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/warley004/packing-optimizer-api/internal/packing"
+)
+
+// Os mesmos cenários valem para qualquer implementação de Repository.
+func repositories(t *testing.T) map[string]Repository {
+	t.Helper()
+
+	bolt, err := NewBoltRepository(filepath.Join(t.TempDir(), "boxes.db"))
+	if err != nil {
+		t.Fatalf("open bolt repository: %v", err)
+	}
+	t.Cleanup(func() { bolt.Close() })
+
+	return map[string]Repository{
+		"memory": NewMemoryRepository(),
+		"bolt":   bolt,
+	}
+}
+
+func TestRepository_Lifecycle(t *testing.T) {
+	for name, repo := range repositories(t) {
+		t.Run(name, func(t *testing.T) {
+			seeded, err := Seed(repo, packing.AvailableBoxes())
+			if err != nil || !seeded {
+				t.Fatalf("expected seed to populate empty repository, got %v %v", seeded, err)
+			}
+
+			if err := repo.Create(packing.BoxType{ID: "Caixa 1", Height: 1, Width: 1, Length: 1}); !errors.Is(err, ErrAlreadyExists) {
+				t.Fatalf("expected ErrAlreadyExists, got %v", err)
+			}
+			if err := repo.Create(packing.BoxType{ID: "Nova", Height: 0, Width: 1, Length: 1}); !errors.Is(err, ErrInvalidBox) {
+				t.Fatalf("expected ErrInvalidBox, got %v", err)
+			}
+
+			if err := repo.Update(packing.BoxType{ID: "Caixa 2", Height: 55, Width: 50, Length: 40}); err != nil {
+				t.Fatalf("unexpected update error: %v", err)
+			}
+			if e, _ := repo.Get("Caixa 2"); e.BoxType.Height != 55 {
+				t.Fatalf("expected updated height 55, got %+v", e)
+			}
+			if err := repo.Update(packing.BoxType{ID: "Inexistente", Height: 1, Width: 1, Length: 1}); !errors.Is(err, ErrNotFound) {
+				t.Fatalf("expected ErrNotFound, got %v", err)
+			}
+
+			if err := repo.Retire("Caixa 1"); err != nil {
+				t.Fatalf("unexpected retire error: %v", err)
+			}
+			if err := repo.Retire("Caixa 2"); err != nil {
+				t.Fatalf("unexpected retire error: %v", err)
+			}
+			if err := repo.Retire("Caixa 3"); !errors.Is(err, ErrLastActiveBox) {
+				t.Fatalf("expected ErrLastActiveBox, got %v", err)
+			}
+
+			active, err := ActiveBoxes(repo)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(active) != 1 || active[0].ID != "Caixa 3" {
+				t.Fatalf("expected only Caixa 3 active, got %+v", active)
+			}
+
+			if seeded, _ := Seed(repo, packing.AvailableBoxes()); seeded {
+				t.Fatalf("seed must not overwrite a populated repository")
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	BoxStoreMemory = "memory"
+	BoxStoreBolt   = "bolt"
+)
+
+// Config concentra as configurações de inicialização; flags têm precedência sobre variáveis de ambiente.
+type Config struct {
+	// BoxCatalogPath aponta para o catálogo de caixas (JSON/YAML) usado para popular um repositório vazio.
+	// Vazio usa as caixas padrão do enunciado.
+	BoxCatalogPath string
+	// BoxStore escolhe onde o catálogo gerenciado por /v1/boxes fica armazenado: "memory" ou "bolt".
+	BoxStore string
+	// BoxStorePath é o arquivo BoltDB usado quando BoxStore = "bolt".
+	BoxStorePath string
+	// ExactMaxItems é o maior pedido (em itens) resolvido pela busca exata; acima disso vale só a heurística. Zero desliga.
+	ExactMaxItems int
+	// JobQueueSize limita os jobs assíncronos aguardando processamento em /v1/packing/jobs.
+	JobQueueSize int
+	// JobResultTTL é por quanto tempo um job finalizado e seu resultado ficam disponíveis para consulta.
+	JobResultTTL time.Duration
+	// RequestTimeout é o prazo de um POST /v1/packing síncrono; zero desliga.
+	RequestTimeout time.Duration
+	// PoolWorkers é o número de workers de empacotamento compartilhados por todos os requests; zero usa o número de CPUs.
+	PoolWorkers int
+	// PoolQueueSize limita os pedidos aguardando workers; lotes síncronos que encontram a fila ocupada são recusados com 503.
+	PoolQueueSize int
+}
+
+func Load(args []string) (Config, error) {
+	var cfg Config
+
+	fs := flag.NewFlagSet("api", flag.ContinueOnError)
+	fs.StringVar(&cfg.BoxCatalogPath, "boxes", os.Getenv("BOX_CATALOG_PATH"), "caminho do catálogo de caixas (.json, .yaml ou .yml); env BOX_CATALOG_PATH")
+	fs.StringVar(&cfg.BoxStore, "box-store", envOr("BOX_STORE", BoxStoreMemory), "armazenamento do catálogo: memory ou bolt; env BOX_STORE")
+	fs.StringVar(&cfg.BoxStorePath, "box-store-path", envOr("BOX_STORE_PATH", "boxes.db"), "arquivo BoltDB do catálogo; env BOX_STORE_PATH")
+
+	exactDefault, err := envIntOr("EXACT_MAX_ITEMS", 8)
+	if err != nil {
+		return Config{}, err
+	}
+	fs.IntVar(&cfg.ExactMaxItems, "exact-max-items", exactDefault, "itens máximos por pedido para a busca exata (0 desliga); env EXACT_MAX_ITEMS")
+
+	queueDefault, err := envIntOr("JOB_QUEUE_SIZE", 100)
+	if err != nil {
+		return Config{}, err
+	}
+	fs.IntVar(&cfg.JobQueueSize, "job-queue-size", queueDefault, "jobs assíncronos aguardando na fila; env JOB_QUEUE_SIZE")
+
+	ttlDefault, err := envDurationOr("JOB_RESULT_TTL", time.Hour)
+	if err != nil {
+		return Config{}, err
+	}
+	fs.DurationVar(&cfg.JobResultTTL, "job-result-ttl", ttlDefault, "tempo de retenção de jobs finalizados (ex.: 30m, 2h); env JOB_RESULT_TTL")
+
+	timeoutDefault, err := envDurationOr("REQUEST_TIMEOUT", 30*time.Second)
+	if err != nil {
+		return Config{}, err
+	}
+	fs.DurationVar(&cfg.RequestTimeout, "request-timeout", timeoutDefault, "prazo do empacotamento síncrono (0 desliga); env REQUEST_TIMEOUT")
+
+	workersDefault, err := envIntOr("POOL_WORKERS", 0)
+	if err != nil {
+		return Config{}, err
+	}
+	fs.IntVar(&cfg.PoolWorkers, "pool-workers", workersDefault, "workers de empacotamento compartilhados (0 usa o número de CPUs); env POOL_WORKERS")
+
+	poolQueueDefault, err := envIntOr("POOL_QUEUE_SIZE", 1024)
+	if err != nil {
+		return Config{}, err
+	}
+	fs.IntVar(&cfg.PoolQueueSize, "pool-queue-size", poolQueueDefault, "pedidos aguardando workers, somando todos os requests; env POOL_QUEUE_SIZE")
+
+	if err := fs.Parse(args); err != nil {
+		return Config{}, err
+	}
+
+	if cfg.BoxStore != BoxStoreMemory && cfg.BoxStore != BoxStoreBolt {
+		return Config{}, fmt.Errorf("box store '%s' inválido: use %s ou %s", cfg.BoxStore, BoxStoreMemory, BoxStoreBolt)
+	}
+
+	if cfg.ExactMaxItems < 0 {
+		return Config{}, fmt.Errorf("exact-max-items inválido: %d (use 0 ou mais)", cfg.ExactMaxItems)
+	}
+
+	if cfg.JobQueueSize <= 0 {
+		return Config{}, fmt.Errorf("job-queue-size inválido: %d (use 1 ou mais)", cfg.JobQueueSize)
+	}
+
+	if cfg.JobResultTTL <= 0 {
+		return Config{}, fmt.Errorf("job-result-ttl inválido: %s (use uma duração positiva)", cfg.JobResultTTL)
+	}
+
+	if cfg.RequestTimeout < 0 {
+		return Config{}, fmt.Errorf("request-timeout inválido: %s (use 0 ou uma duração positiva)", cfg.RequestTimeout)
+	}
+
+	if cfg.PoolWorkers < 0 {
+		return Config{}, fmt.Errorf("pool-workers inválido: %d (use 0 ou mais)", cfg.PoolWorkers)
+	}
+
+	if cfg.PoolQueueSize <= 0 {
+		return Config{}, fmt.Errorf("pool-queue-size inválido: %d (use 1 ou mais)", cfg.PoolQueueSize)
+	}
+
+	return cfg, nil
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+func envIntOr(key string, fallback int) (int, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("%s inválido: %q", key, v)
+	}
+	return n, nil
+}
+
+func envDurationOr(key string, fallback time.Duration) (time.Duration, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("%s inválido: %q", key, v)
+	}
+	return d, nil
+}
@@ -0,0 +1,161 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Metrics concentra as métricas Prometheus da API, expostas em /metrics. Cada instância tem seu próprio registro,
+// então testes podem criar quantas quiserem. Os métodos aceitam receptor nil, que desliga a coleta.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	requests *prometheus.CounterVec
+	latency  *prometheus.HistogramVec
+
+	orderItems      prometheus.Histogram
+	orderBoxes      prometheus.Histogram
+	orderDuration   prometheus.Histogram
+	boxFill         prometheus.Histogram
+	unpackableItems *prometheus.CounterVec
+}
+
+// PoolStats é o retrato do pool de workers lido a cada coleta.
+type PoolStats struct {
+	Workers int
+	Limit   int
+	Queued  int
+	Active  int
+	Lanes   int
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "Requests HTTP atendidos, por método, rota e status.",
+		}, []string{"method", "route", "status"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "Duração dos requests HTTP, por método, rota e status (streams contam até o fim do fluxo).",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		orderItems: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "packing_order_items",
+			Help:    "Unidades de produto por pedido empacotado.",
+			Buckets: prometheus.ExponentialBuckets(1, 2, 10),
+		}),
+		orderBoxes: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "packing_order_boxes",
+			Help:    "Caixas usadas por pedido empacotado.",
+			Buckets: prometheus.ExponentialBuckets(1, 2, 8),
+		}),
+		orderDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "packing_order_duration_seconds",
+			Help:    "Tempo de PackOrder por pedido, heurística e busca exata incluídas.",
+			Buckets: prometheus.ExponentialBuckets(0.0005, 2, 14),
+		}),
+		boxFill: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "packing_box_fill_ratio",
+			Help:    "Ocupação de cada caixa usada (volume ocupado sobre volume interno, de 0 a 1).",
+			Buckets: prometheus.LinearBuckets(0.1, 0.1, 10),
+		}),
+		unpackableItems: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "packing_unpackable_items_total",
+			Help: "Unidades que não couberam em nenhuma caixa, por motivo.",
+		}, []string{"reason"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests, m.latency,
+		m.orderItems, m.orderBoxes, m.orderDuration, m.boxFill, m.unpackableItems,
+	)
+	return m
+}
+
+// Handler serve as métricas no formato de exposição do Prometheus.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// Middleware conta e cronometra os requests. A rota é o padrão registrado (ex.: /v1/boxes/:id), não o path,
+// para manter a cardinalidade fixa; requests sem rota contam como "unmatched".
+func (m *Metrics) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if m == nil {
+			c.Next()
+			return
+		}
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := strconv.Itoa(c.Writer.Status())
+		m.requests.WithLabelValues(c.Request.Method, route, status).Inc()
+		m.latency.WithLabelValues(c.Request.Method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
+
+// ObservePackDuration registra o tempo de um PackOrder concluído ou rejeitado (cancelamentos não contam).
+func (m *Metrics) ObservePackDuration(d time.Duration) {
+	if m == nil {
+		return
+	}
+	m.orderDuration.Observe(d.Seconds())
+}
+
+// ObserveOrder registra um pedido empacotado: unidades de produto, caixas usadas e a ocupação de cada caixa (0 a 1).
+func (m *Metrics) ObserveOrder(items int, fillRates []float64) {
+	if m == nil {
+		return
+	}
+	m.orderItems.Observe(float64(items))
+	m.orderBoxes.Observe(float64(len(fillRates)))
+	for _, fill := range fillRates {
+		m.boxFill.Observe(fill)
+	}
+}
+
+// ObserveUnpackable conta n unidades deixadas de fora pelo motivo informado.
+func (m *Metrics) ObserveUnpackable(reason string, n int) {
+	if m == nil || n <= 0 {
+		return
+	}
+	m.unpackableItems.WithLabelValues(reason).Add(float64(n))
+}
+
+// RegisterPool expõe gauges do pool de workers; stats é chamado a cada coleta.
+func (m *Metrics) RegisterPool(stats func() PoolStats) {
+	if m == nil {
+		return
+	}
+	gauge := func(name, help string, value func(PoolStats) int) prometheus.Collector {
+		return prometheus.NewGaugeFunc(prometheus.GaugeOpts{Name: name, Help: help}, func() float64 {
+			return float64(value(stats()))
+		})
+	}
+	m.registry.MustRegister(
+		gauge("packing_pool_workers", "Workers do pool de empacotamento.",
+			func(s PoolStats) int { return s.Workers }),
+		gauge("packing_pool_queue_limit", "Limite de pedidos aguardando workers.",
+			func(s PoolStats) int { return s.Limit }),
+		gauge("packing_pool_queued_orders", "Pedidos aguardando workers (inclui vagas reservadas por lotes síncronos).",
+			func(s PoolStats) int { return s.Queued }),
+		gauge("packing_pool_active_workers", "Workers empacotando um pedido neste momento.",
+			func(s PoolStats) int { return s.Active }),
+		gauge("packing_pool_lanes", "Lotes com pedidos na fila, atendidos em rodízio.",
+			func(s PoolStats) int { return s.Lanes }),
+	)
+}
@@ -0,0 +1,78 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func scrape(t *testing.T, m *Metrics) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, err := io.ReadAll(rec.Body)
+	if err != nil {
+		t.Fatalf("read metrics: %v", err)
+	}
+	return string(body)
+}
+
+func expectLines(t *testing.T, body string, lines ...string) {
+	t.Helper()
+	for _, line := range lines {
+		if !strings.Contains(body, line+"\n") {
+			t.Fatalf("expected metric line %q in:\n%s", line, body)
+		}
+	}
+}
+
+func TestMiddleware_LabelsByRoutePattern(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	m := New()
+	r := gin.New()
+	r.Use(m.Middleware())
+	r.GET("/v1/boxes/:id", func(c *gin.Context) { c.Status(http.StatusNotFound) })
+
+	for _, path := range []string{"/v1/boxes/a", "/v1/boxes/b", "/nada"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	expectLines(t, scrape(t, m),
+		`http_requests_total{method="GET",route="/v1/boxes/:id",status="404"} 2`,
+		`http_requests_total{method="GET",route="unmatched",status="404"} 1`,
+		`http_request_duration_seconds_count{method="GET",route="/v1/boxes/:id",status="404"} 2`,
+	)
+}
+
+func TestMetrics_PackingObservationsAndPoolGauges(t *testing.T) {
+	m := New()
+	m.ObserveOrder(3, []float64{0.5, 0.95})
+	m.ObservePackDuration(2 * time.Millisecond)
+	m.ObserveUnpackable("excede_peso", 2)
+	m.RegisterPool(func() PoolStats { return PoolStats{Workers: 4, Limit: 10, Queued: 3, Active: 2, Lanes: 1} })
+
+	expectLines(t, scrape(t, m),
+		`packing_order_items_sum 3`,
+		`packing_order_boxes_sum 2`,
+		`packing_box_fill_ratio_count 2`,
+		`packing_box_fill_ratio_bucket{le="0.5"} 1`,
+		`packing_order_duration_seconds_count 1`,
+		`packing_unpackable_items_total{reason="excede_peso"} 2`,
+		`packing_pool_workers 4`,
+		`packing_pool_queued_orders 3`,
+		`packing_pool_active_workers 2`,
+	)
+}
+
+func TestMetrics_NilIsNoop(t *testing.T) {
+	var m *Metrics
+	m.ObserveOrder(1, []float64{1})
+	m.ObservePackDuration(time.Millisecond)
+	m.ObserveUnpackable("muito_grande", 1)
+	m.RegisterPool(func() PoolStats { return PoolStats{} })
+}
@@ -1,6 +1,7 @@
 package packing
 
 import (
+	"context"
 	"fmt"
 	"sort"
 )
@@ -43,147 +44,304 @@ func (d Dimensions) FitsIn(space Dimensions) bool {
 	return d.Height <= space.Height && d.Width <= space.Width && d.Length <= space.Length
 }
 
-// rotationsFor devolve todas as rotações quando allowRotation=true ou apenas a orientação original.
-// allowRotation define se mantemos orientação rígida (false) ou testamos todas as permutações 3D (true).
-func rotationsFor(d Dimensions, allowRotation bool) []Dimensions {
-	if allowRotation {
-		return d.Rotations()
+// Position é a origem (canto inferior, esquerdo, traseiro) de um espaço ou item dentro da caixa.
+// X acompanha a largura, Y o comprimento e Z a altura, sempre a partir do canto (0, 0, 0) da caixa.
+type Position struct {
+	X int
+	Y int
+	Z int
+}
+
+// Space é um volume livre da caixa ancorado em uma origem.
+type Space struct {
+	Origin Position
+	Dim    Dimensions
+}
+
+// fitsAnyRotation informa se alguma das orientações candidatas cabe no espaço.
+func fitsAnyRotation(rots []Dimensions, space Dimensions) bool {
+	for _, rot := range rots {
+		if rot.FitsIn(space) {
+			return true
+		}
 	}
-	return []Dimensions{d}
+	return false
 }
 
 type Item struct {
 	ProductID string
 	Dim       Dimensions
 	Volume    int
-	Index     int // posição do produto no pedido (ordem original do input)
+	Index     int     // posição do produto no pedido (ordem original do input)
+	Weight    float64 // peso do produto; zero quando não informado
+	// Orientation restringe as rotações do item; vazio segue Options.AllowRotation.
+	Orientation Orientation
+	// Clearance é a folga de proteção (ex.: plástico bolha) somada em cada face do item; ver clearance.go.
+	Clearance int
+	// Fragile proíbe apoiar qualquer item sobre este; MaxLoadOnTop limita o peso (kg) apoiado sobre ele,
+	// direta ou indiretamente (zero = sem limite). Ver stacking.go.
+	Fragile      bool
+	MaxLoadOnTop float64
 }
 
 type packedProduct struct {
-	ID    string
-	Index int
+	ID       string
+	Index    int
+	Position Position   // origem do espaço ocupado pelo item (com folga) dentro da caixa
+	Rotation Dimensions // orientação efetivamente usada (altura, largura, comprimento), com folga
+	item     Item       // item original, para reempacotar a caixa em outro tipo
+	// supporters são os índices (em Products, durante o empacotamento) dos produtos logo abaixo; load é o peso apoiado sobre ele.
+	supporters []int
+	load       float64
 }
 
 type PackedBox struct {
-	BoxType    BoxType
-	Products   []packedProduct
-	freeSpaces []Dimensions
+	BoxType       BoxType
+	Products      []packedProduct
+	ContentWeight float64 // soma dos pesos dos produtos alocados
+	freeSpaces    []Space
+	strategy      FreeSpaceStrategy
+	loadLimited   bool    // algum produto alocado tem limite de carga (ver stacking.go)
+	supportKnown  bool    // a cadeia de apoio dos produtos já alocados está montada
+	minSupport    float64 // fração mínima da base apoiada (ver support.go)
+	balance       bool    // desempata posições pelo centro de gravidade (ver balance.go)
+	weightMoments moments
+	volumeMoments moments
 }
 
-func newPackedBox(bt BoxType) PackedBox {
+func newPackedBox(bt BoxType, opts Options) PackedBox {
 	return PackedBox{
 		BoxType:    bt,
 		Products:   []packedProduct{},
-		freeSpaces: []Dimensions{{Height: bt.Height, Width: bt.Width, Length: bt.Length}},
+		strategy:   opts.FreeSpace,
+		minSupport: opts.MinSupportRatio,
+		balance:    opts.Balance,
+		freeSpaces: []Space{{
+			Origin: Position{},
+			Dim:    bt.InnerDimensions(),
+		}},
 	}
 }
 
+// UsedVolume soma o volume dos produtos alocados.
+func (b *PackedBox) UsedVolume() int {
+	used := 0
+	for _, p := range b.Products {
+		used += p.Rotation.Volume()
+	}
+	return used
+}
+
+// GrossWeight é o peso bruto da caixa: tara + conteúdo.
+func (b *PackedBox) GrossWeight() float64 {
+	return b.BoxType.TareWeight + b.ContentWeight
+}
+
 func (b *PackedBox) boxVolume() int {
 	return b.BoxType.Height * b.BoxType.Width * b.BoxType.Length
 }
 
+// FillRate é a fração (0 a 1) do volume interno da caixa ocupada pelos produtos, folga incluída.
+func (b *PackedBox) FillRate() float64 {
+	if b.boxVolume() == 0 {
+		return 0
+	}
+	return float64(b.UsedVolume()) / float64(b.boxVolume())
+}
+
+// FreeSpaces devolve uma cópia dos espaços livres restantes. Com MaximalSpaces eles podem se sobrepor,
+// então a soma dos volumes não é o volume livre da caixa.
+func (b *PackedBox) FreeSpaces() []Space {
+	return append([]Space(nil), b.freeSpaces...)
+}
+
 type placement struct {
 	spaceIndex  int
 	rot         Dimensions
 	wasteVolume int
+	offset      float64 // desvio do centro de gravidade, só calculado com balanceamento
 }
 
-// TryPlace tenta colocar o item aplicando free-space splitting.
-// Retorna true se o item couber.
+// TryPlace tenta colocar o item no espaço livre de menor desperdício e atualiza os espaços livres da caixa.
+// Retorna true se o item couber e a carga máxima da caixa não for excedida.
 func (b *PackedBox) TryPlace(item Item, allowRotation bool) bool {
+	best, ok := b.findPlacement(item, allowRotation)
+	if !ok {
+		return false
+	}
+
+	// Aloca na origem do espaço escolhido.
+	b.place(item, best.spaceIndex, b.freeSpaces[best.spaceIndex].Origin, best.rot)
+	return true
+}
+
+// findPlacement escolhe o espaço livre e a rotação de menor desperdício sem alterar a caixa.
+// Com balanceamento, empates de desperdício ficam com a posição de menor desvio do centro de gravidade.
+func (b *PackedBox) findPlacement(item Item, allowRotation bool) (placement, bool) {
+	if !b.BoxType.CanCarry(b.ContentWeight + item.Weight) {
+		return placement{}, false
+	}
+
 	best := placement{wasteVolume: int(^uint(0) >> 1)} // max int
 	found := false
+	checkStacking := b.stackingChecked(item)
+	if checkStacking {
+		b.trackSupport()
+	}
 
 	for si, space := range b.freeSpaces {
-		for _, rot := range rotationsFor(item.Dim, allowRotation) {
-			if !rot.FitsIn(space) {
+		for _, rot := range item.rotations(allowRotation) {
+			if !rot.FitsIn(space.Dim) {
 				continue
 			}
 
-			waste := space.Volume() - rot.Volume()
-			if waste < best.wasteVolume {
-				best = placement{
-					spaceIndex:  si,
-					rot:         rot,
-					wasteVolume: waste,
-				}
-				found = true
+			waste := space.Dim.Volume() - rot.Volume()
+			if waste > best.wasteVolume {
+				continue
 			}
+
+			cand := Space{Origin: space.Origin, Dim: rot}
+			offset := 0.0
+			if b.balance {
+				offset = b.balanceOffset(item, cand)
+			}
+			if waste == best.wasteVolume && (!b.balance || offset >= best.offset-costEpsilon) {
+				continue
+			}
+
+			if !b.supported(cand) {
+				continue
+			}
+			if checkStacking && !b.canStack(item, cand) {
+				continue
+			}
+			best = placement{
+				spaceIndex:  si,
+				rot:         rot,
+				wasteVolume: waste,
+				offset:      offset,
+			}
+			found = true
 		}
 	}
 
-	if !found {
-		return false
-	}
+	return best, found
+}
+
+// place registra o item na posição informada e atualiza os espaços livres conforme a estratégia da caixa.
+// spaceIndex identifica o espaço usado (necessário apenas para a guilhotina).
+func (b *PackedBox) place(item Item, spaceIndex int, origin Position, rot Dimensions) {
+	b.occupy(spaceIndex, Space{Origin: origin, Dim: rot})
+	b.record(item, origin, rot)
+}
 
-	// Aloca no espaço escolhido e faz o split determinístico.
-	space := b.freeSpaces[best.spaceIndex]
-	rot := best.rot
-
-	// Remove used space
-	b.freeSpaces = append(b.freeSpaces[:best.spaceIndex], b.freeSpaces[best.spaceIndex+1:]...)
-
-	// Estratégia de split (tipo guilhotina, simples e determinística) mantém layout previsível para encaixes futuros.
-	// Coloca o item na origem do espaço; gera até 3 sobras.
-	// 1) Slice lateral (largura restante)
-	if space.Width-rot.Width > 0 {
-		b.freeSpaces = append(b.freeSpaces, Dimensions{
-			Height: space.Height,
-			Width:  space.Width - rot.Width,
-			Length: space.Length,
-		})
+// occupy retira dos espaços livres o volume ocupado, conforme a estratégia da caixa.
+func (b *PackedBox) occupy(spaceIndex int, placed Space) {
+	switch b.strategy {
+	case MaximalSpaces:
+		b.freeSpaces = splitMaximal(b.freeSpaces, placed)
+	default:
+		b.freeSpaces = splitGuillotine(b.freeSpaces, spaceIndex, placed)
 	}
-	// 2) Slice frontal (comprimento restante) dentro da largura do item
-	if space.Length-rot.Length > 0 {
-		b.freeSpaces = append(b.freeSpaces, Dimensions{
-			Height: space.Height,
-			Width:  rot.Width,
-			Length: space.Length - rot.Length,
-		})
+}
+
+// record adiciona o produto à caixa; com limites de carga em jogo, também o liga à cadeia de apoio (ver stacking.go).
+func (b *PackedBox) record(item Item, origin Position, rot Dimensions) {
+	p := packedProduct{
+		ID:       item.ProductID,
+		Index:    item.Index,
+		Position: origin,
+		Rotation: rot,
+		item:     item,
 	}
-	// 3) Slice superior (altura restante) dentro da base do item
-	if space.Height-rot.Height > 0 {
-		b.freeSpaces = append(b.freeSpaces, Dimensions{
-			Height: space.Height - rot.Height,
-			Width:  rot.Width,
-			Length: rot.Length,
-		})
+	if b.stackingChecked(item) {
+		b.trackSupport()
+		b.link(&p)
 	}
 
-	// Mantém os espaços ordenados por volume decrescente para tentar áreas maiores primeiro.
-	sort.Slice(b.freeSpaces, func(i, j int) bool {
-		return b.freeSpaces[i].Volume() > b.freeSpaces[j].Volume()
-	})
-
-	b.Products = append(b.Products, packedProduct{ID: item.ProductID, Index: item.Index})
-	return true
+	b.Products = append(b.Products, p)
+	b.ContentWeight += item.Weight
+	b.addMoments(item.Weight, p.space())
+	b.loadLimited = b.loadLimited || item.loadLimited()
 }
 
 type OrderPackingResult struct {
 	Boxes []PackedBox
+	// Optimal indica que o número de caixas é comprovadamente mínimo: atinge o limite inferior
+	// ou foi confirmado pela busca exata (ver exact.go).
+	Optimal bool
+	// LowerBound traz os limites inferiores do número de caixas (ver bounds.go), para medir o gap da solução.
+	LowerBound LowerBound
+	// Unpacked lista, na ordem original do pedido, os itens deixados de fora com Options.AllowPartial.
+	Unpacked []UnpackedItem
+}
+
+// Options reúne os parâmetros do empacotamento.
+// O valor zero usa orientação rígida, split em guilhotina, menor caixa primeiro e FirstFitDecreasing; a diferença
+// para a heurística original é a alocação em blocos das unidades de uma mesma linha (ver packUnits).
+type Options struct {
+	AllowRotation bool
+	FreeSpace     FreeSpaceStrategy
+	// Objective, quando informado, compara variações da heurística e mantém a de menor custo (ver objective.go).
+	Objective Objective
+	// Packer escolhe a estratégia de empacotamento (ver packer.go); nil usa FirstFitDecreasing, o comportamento original.
+	Packer Packer
+	// AllowPartial deixa de fora os itens que não cabem em nenhuma caixa (ver OrderPackingResult.Unpacked)
+	// em vez de falhar o pedido inteiro.
+	AllowPartial bool
+	// MinSupportRatio é a fração mínima (0 a 1) da base de cada item que precisa estar apoiada no piso ou no topo
+	// de outros itens; zero desliga a verificação. Ver support.go.
+	MinSupportRatio float64
+	// Balance desempata posições de mesmo desperdício pela que mantém o centro de gravidade mais perto
+	// do centro da caixa (ver balance.go).
+	Balance bool
+	// ExactMaxItems habilita a busca exata para pedidos com até esse número de itens; zero desliga.
+	// Só se aplica ao objetivo de número de caixas.
+	ExactMaxItems int
+
+	// noBlocks aloca as unidades uma a uma, sem blocos (ver packUnits).
+	noBlocks bool
+
+	// ctx é definido por PackOrderContext; as estratégias consultam canceled entre um item (ou caixa) e outro.
+	ctx context.Context
+}
+
+// canceled devolve o erro do contexto do empacotamento, se ele já foi cancelado ou expirou.
+func (o Options) canceled() error {
+	if o.ctx == nil {
+		return nil
+	}
+	return o.ctx.Err()
 }
 
 // PackOrder empacota itens com uma heurística determinística para o problema NP-difícil de bin packing 3D; busca minimizar caixas abertas, mas não garante ótimo global.
 // Retorna erro se algum item não couber em nenhuma caixa disponível.
 func PackOrder(items []Item, boxTypes []BoxType, allowRotation bool) (OrderPackingResult, error) {
+	return PackOrderWithOptions(items, boxTypes, Options{AllowRotation: allowRotation})
+}
+
+// PackOrderWithOptions executa a mesma heurística de PackOrder permitindo escolher o gerenciador de espaços livres
+// e o objetivo de otimização.
+func PackOrderWithOptions(items []Item, boxTypes []BoxType, opts Options) (OrderPackingResult, error) {
+	return PackOrderContext(context.Background(), items, boxTypes, opts)
+}
+
+// PackOrderContext é PackOrderWithOptions com cancelamento: quando o contexto é cancelado ou expira, as estratégias
+// e a busca exata param na próxima verificação e o erro do contexto é devolvido.
+func PackOrderContext(ctx context.Context, items []Item, boxTypes []BoxType, opts Options) (OrderPackingResult, error) {
+	opts.ctx = ctx
 	if len(items) == 0 {
 		return OrderPackingResult{Boxes: []PackedBox{}}, nil
 	}
 
 	// Problema NP-difícil tratado via heurística determinística para reduzir caixas abertas.
 
-	// Ordena itens por volume decrescente (FFD) para que maiores ocupem primeiro, reduzindo fragmentação.
-	for i := range items {
-		items[i].Volume = items[i].Dim.Volume()
-	}
-	sort.Slice(items, func(i, j int) bool {
-		if items[i].Volume == items[j].Volume {
-			return items[i].ProductID < items[j].ProductID
-		}
-		return items[i].Volume > items[j].Volume
-	})
+	items = withClearance(items)
+	sortItemsByVolume(items)
 
 	// Ordena caixas por volume crescente para testar primeiro o menor recipiente viável.
+	// Trabalha sobre uma cópia: o catálogo é compartilhado entre pedidos processados em paralelo.
+	boxTypes = append([]BoxType(nil), boxTypes...)
 	sort.Slice(boxTypes, func(i, j int) bool {
 		vi := boxTypes[i].Height * boxTypes[i].Width * boxTypes[i].Length
 		vj := boxTypes[j].Height * boxTypes[j].Width * boxTypes[j].Length
@@ -193,69 +351,124 @@ func PackOrder(items []Item, boxTypes []BoxType, allowRotation bool) (OrderPacki
 		return vi < vj
 	})
 
-	var opened []PackedBox
+	var unpacked []UnpackedItem
+	if opts.AllowPartial {
+		items, unpacked = splitUnpackable(items, boxTypes, opts)
+		if len(items) == 0 {
+			return OrderPackingResult{Boxes: []PackedBox{}, Unpacked: unpacked}, nil
+		}
+	}
 
-	for _, it := range items {
-		placed := false
+	var res OrderPackingResult
+	var err error
+	if opts.Objective != nil {
+		res, err = packForObjective(items, boxTypes, opts)
+	} else {
+		res, err = packUnits(items, boxTypes, opts)
+	}
+	if err != nil {
+		return OrderPackingResult{}, err
+	}
 
-		// Try existing boxes first
-		for bi := range opened {
-			if opened[bi].TryPlace(it, allowRotation) {
-				placed = true
-				break
-			}
-		}
+	lb := lowerBound(items, boxTypes, opts)
+	res.Optimal = len(res.Boxes) <= lb.Value
 
-		if placed {
-			continue
+	if !res.Optimal && exactApplies(items, opts) {
+		res = packExact(items, boxTypes, opts, res, lb.Value)
+	}
+	if res.Optimal {
+		// Ótimo comprovado: nenhuma solução usa menos caixas, então o número de caixas é o limite e o gap é zero.
+		lb.Value = max(lb.Value, len(res.Boxes))
+	}
+	if err := ctx.Err(); err != nil {
+		// A estratégia pode ter terminado com um resultado parcial da busca interrompida.
+		return OrderPackingResult{}, err
+	}
+	res.LowerBound = lb
+	res.Unpacked = unpacked
+	return res, nil
+}
+
+// sortItemsByVolume ordena itens por volume decrescente (FFD) para que maiores ocupem primeiro, reduzindo fragmentação.
+func sortItemsByVolume(items []Item) {
+	for i := range items {
+		items[i].Volume = items[i].Dim.Volume()
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Volume == items[j].Volume {
+			// Desempate por produto e posição no pedido mantém adjacentes as unidades de uma linha (ver runEnd).
+			if items[i].ProductID == items[j].ProductID {
+				return items[i].Index < items[j].Index
+			}
+			return items[i].ProductID < items[j].ProductID
 		}
+		return items[i].Volume > items[j].Volume
+	})
+}
 
-		// Abrir nova caixa: prefere encaixar sem rotação; recorre à rotação se necessário e permitido.
-		noRotationIdx := -1
-		rotationIdx := -1
+// openBox abre a caixa que receberá o item quando nenhuma caixa aberta o comporta (ver chooseBoxType).
+func openBox(it Item, boxTypes []BoxType, opts Options) (PackedBox, error) {
+	chosenIdx, err := chooseBoxType(it, boxTypes, opts)
+	if err != nil {
+		return PackedBox{}, err
+	}
 
-		for i := range boxTypes {
-			bt := boxTypes[i]
-			space := Dimensions{Height: bt.Height, Width: bt.Width, Length: bt.Length}
+	chosen := boxTypes[chosenIdx]
 
-			if it.Dim.FitsIn(space) {
-				noRotationIdx = i
-				break
-			}
+	nb := newPackedBox(chosen, opts)
+	if !nb.TryPlace(it, opts.AllowRotation) {
+		// Não deve acontecer após a checagem de ajuste, mas mantemos validação defensiva.
+		return PackedBox{}, fmt.Errorf("falha inesperada ao alocar produto '%s' na caixa '%s'", it.ProductID, chosen.ID)
+	}
+	return nb, nil
+}
 
-			if allowRotation && rotationIdx == -1 {
-				for _, rot := range it.Dim.Rotations() {
-					if rot.FitsIn(space) {
-						rotationIdx = i
-						break
-					}
-				}
-			}
+// chooseBoxType escolhe o tipo de caixa para abrir com o item: o primeiro, na ordem de preferência, em que ele cabe
+// sem rotação; senão, o primeiro em que cabe com as rotações permitidas. Caixas cuja carga máxima não comporta
+// o item são ignoradas. Se nenhuma servir, o *UnpackableError explica o motivo.
+func chooseBoxType(it Item, boxTypes []BoxType, opts Options) (int, error) {
+	rots := it.rotations(opts.AllowRotation)
+	noRotationIdx := -1
+	rotationIdx := -1
+	tooHeavy := false
+
+	for i := range boxTypes {
+		bt := boxTypes[i]
+		space := Dimensions{Height: bt.Height, Width: bt.Width, Length: bt.Length}
+
+		if !bt.CanCarry(it.Weight) {
+			tooHeavy = tooHeavy || fitsAnyRotation(rots, space)
+			continue
 		}
 
-		chosenIdx := -1
-		if noRotationIdx != -1 {
-			chosenIdx = noRotationIdx
-		} else if rotationIdx != -1 {
-			chosenIdx = rotationIdx
+		if it.Dim.FitsIn(space) {
+			noRotationIdx = i
+			break
 		}
 
-		if chosenIdx == -1 {
-			if allowRotation {
-				return OrderPackingResult{}, fmt.Errorf("produto '%s' não cabe em nenhuma caixa disponível (mesmo com rotação)", it.ProductID)
-			}
-			return OrderPackingResult{}, fmt.Errorf("produto '%s' não cabe em nenhuma caixa disponível", it.ProductID)
+		if rotationIdx == -1 && fitsAnyRotation(rots, space) {
+			rotationIdx = i
 		}
+	}
 
-		chosen := boxTypes[chosenIdx]
-
-		nb := newPackedBox(chosen)
-		if !nb.TryPlace(it, allowRotation) {
-			// Não deve acontecer após a checagem de ajuste, mas mantemos validação defensiva.
-			return OrderPackingResult{}, fmt.Errorf("falha inesperada ao alocar produto '%s' na caixa '%s'", it.ProductID, chosen.ID)
-		}
-		opened = append(opened, nb)
+	if noRotationIdx != -1 {
+		return noRotationIdx, nil
+	}
+	if rotationIdx != -1 {
+		return rotationIdx, nil
 	}
 
-	return OrderPackingResult{Boxes: opened}, nil
+	reason := ReasonTooLarge
+	msg := fmt.Sprintf("produto '%s' não cabe em nenhuma caixa disponível", it.ProductID)
+	switch {
+	case tooHeavy:
+		reason = ReasonOverWeight
+		msg = fmt.Sprintf("produto '%s' (peso %g) excede a carga máxima de todas as caixas em que cabe", it.ProductID, it.Weight)
+	case it.orientationLocked(boxTypes):
+		reason = ReasonOrientationLocked
+		msg = fmt.Sprintf("produto '%s' não cabe em nenhuma caixa respeitando a orientação '%s'", it.ProductID, it.Orientation)
+	case len(rots) > 1:
+		msg = fmt.Sprintf("produto '%s' não cabe em nenhuma caixa disponível (mesmo com rotação)", it.ProductID)
+	}
+	return -1, &UnpackableError{ProductID: it.ProductID, Reason: reason, msg: msg}
 }
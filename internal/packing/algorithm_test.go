@@ -115,3 +115,124 @@ func TestPackOrder_ItemUnpackableReturnsError_NoRotation(t *testing.T) {
 		t.Fatalf("expected error, got nil")
 	}
 }
+
+func TestPackOrder_RecordsNonOverlappingPositionsInsideBox(t *testing.T) {
+	items := []Item{
+		{ProductID: "A", Dim: Dimensions{Height: 30, Width: 20, Length: 40}, Index: 0},
+		{ProductID: "B", Dim: Dimensions{Height: 30, Width: 20, Length: 40}, Index: 1},
+		{ProductID: "C", Dim: Dimensions{Height: 10, Width: 40, Length: 40}, Index: 2},
+	}
+
+	res, err := PackOrder(items, AvailableBoxes(), true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, b := range res.Boxes {
+		for i, p := range b.Products {
+			end := Position{X: p.Position.X + p.Rotation.Width, Y: p.Position.Y + p.Rotation.Length, Z: p.Position.Z + p.Rotation.Height}
+			if p.Position.X < 0 || p.Position.Y < 0 || p.Position.Z < 0 ||
+				end.X > b.BoxType.Width || end.Y > b.BoxType.Length || end.Z > b.BoxType.Height {
+				t.Fatalf("product %s outside box %s: %+v %+v", p.ID, b.BoxType.ID, p.Position, p.Rotation)
+			}
+
+			for _, q := range b.Products[i+1:] {
+				if overlaps(p, q) {
+					t.Fatalf("products %s and %s overlap", p.ID, q.ID)
+				}
+			}
+		}
+	}
+}
+
+func overlaps(a, b packedProduct) bool {
+	return a.Position.X < b.Position.X+b.Rotation.Width && b.Position.X < a.Position.X+a.Rotation.Width &&
+		a.Position.Y < b.Position.Y+b.Rotation.Length && b.Position.Y < a.Position.Y+a.Rotation.Length &&
+		a.Position.Z < b.Position.Z+b.Rotation.Height && b.Position.Z < a.Position.Z+a.Rotation.Height
+}
+
+func TestPackOrder_OpensNewBoxWhenPayloadLimitIsReached(t *testing.T) {
+	boxes := []BoxType{{ID: "Leve", Height: 50, Width: 50, Length: 50, MaxWeight: 20, TareWeight: 0.5}}
+	items := []Item{
+		{ProductID: "Ferro1", Dim: Dimensions{Height: 10, Width: 10, Length: 10}, Weight: 12, Index: 0},
+		{ProductID: "Ferro2", Dim: Dimensions{Height: 10, Width: 10, Length: 10}, Weight: 12, Index: 1},
+	}
+
+	res, err := PackOrder(items, boxes, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Geometricamente cabem juntos, mas 24 kg excedem os 20 kg da caixa.
+	if len(res.Boxes) != 2 {
+		t.Fatalf("expected 2 boxes, got %d", len(res.Boxes))
+	}
+	if got := res.Boxes[0].GrossWeight(); got != 12.5 {
+		t.Fatalf("expected gross weight 12.5, got %v", got)
+	}
+}
+
+func TestPackOrder_ItemHeavierThanEveryBoxReturnsError(t *testing.T) {
+	boxes := []BoxType{{ID: "Leve", Height: 50, Width: 50, Length: 50, MaxWeight: 20}}
+	items := []Item{
+		{ProductID: "Bigorna", Dim: Dimensions{Height: 10, Width: 10, Length: 10}, Weight: 60, Index: 0},
+	}
+
+	if _, err := PackOrder(items, boxes, true); err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+}
+
+func TestPackOrder_AllowPartialReportsUnpackedItems(t *testing.T) {
+	boxes := []BoxType{{ID: "Pequena", Height: 10, Width: 10, Length: 20, MaxWeight: 5}}
+	items := []Item{
+		{ProductID: "Grande", Dim: Dimensions{Height: 30, Width: 5, Length: 5}, Index: 0},
+		{ProductID: "Livro", Dim: Dimensions{Height: 2, Width: 5, Length: 5}, Weight: 1, Index: 1},
+		{ProductID: "Haltere", Dim: Dimensions{Height: 5, Width: 5, Length: 5}, Weight: 8, Index: 2},
+		{ProductID: "Vaso", Dim: Dimensions{Height: 15, Width: 5, Length: 5}, Orientation: OrientationUpright, Index: 3},
+	}
+
+	if _, err := PackOrderWithOptions(append([]Item(nil), items...), boxes, Options{AllowRotation: true}); err == nil {
+		t.Fatalf("expected error without AllowPartial")
+	}
+
+	res, err := PackOrderWithOptions(items, boxes, Options{AllowRotation: true, AllowPartial: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Boxes) != 1 || len(res.Boxes[0].Products) != 1 || res.Boxes[0].Products[0].ID != "Livro" {
+		t.Fatalf("expected only Livro packed, got %+v", res.Boxes)
+	}
+
+	want := []struct {
+		id     string
+		reason UnpackedReason
+	}{
+		{"Grande", ReasonTooLarge},
+		{"Haltere", ReasonOverWeight},
+		{"Vaso", ReasonOrientationLocked},
+	}
+	if len(res.Unpacked) != len(want) {
+		t.Fatalf("expected %d unpacked items, got %+v", len(want), res.Unpacked)
+	}
+	for i, w := range want {
+		if got := res.Unpacked[i]; got.Item.ProductID != w.id || got.Reason != w.reason {
+			t.Fatalf("unpacked[%d]: expected %s/%s, got %s/%s", i, w.id, w.reason, got.Item.ProductID, got.Reason)
+		}
+	}
+}
+
+func TestPackedBox_FillRateAndFreeSpaces(t *testing.T) {
+	b := newPackedBox(BoxType{ID: "Cubo", Height: 10, Width: 10, Length: 10}, Options{})
+	if !b.TryPlace(Item{ProductID: "A", Dim: Dimensions{Height: 5, Width: 10, Length: 10}}, false) {
+		t.Fatalf("expected item to fit")
+	}
+
+	if got := b.FillRate(); got != 0.5 {
+		t.Fatalf("expected fill rate 0.5, got %v", got)
+	}
+	want := []Space{{Origin: Position{Z: 5}, Dim: Dimensions{Height: 5, Width: 10, Length: 10}}}
+	if got := b.FreeSpaces(); len(got) != 1 || got[0] != want[0] {
+		t.Fatalf("expected free spaces %+v, got %+v", want, got)
+	}
+}
@@ -0,0 +1,74 @@
+package packing
+
+// Balanceamento: cada caixa acumula os momentos dos produtos alocados para informar o centro de gravidade.
+// O centro é ponderado pelo peso; quando nenhum peso é conhecido, assume densidade uniforme e pondera pelo volume.
+// Com Options.Balance, findPlacement desempata posições de mesmo desperdício pela que deixa o centro de gravidade
+// mais perto do centro da caixa no plano do piso (x, y).
+
+// Point é uma coordenada contínua dentro da caixa, nos mesmos eixos de Position.
+type Point struct {
+	X float64
+	Y float64
+	Z float64
+}
+
+// moments acumula massa e momento (massa × centro) de um conjunto de volumes.
+type moments struct {
+	mass    float64
+	x, y, z float64
+}
+
+func (m *moments) add(mass float64, c Point) {
+	m.mass += mass
+	m.x += mass * c.X
+	m.y += mass * c.Y
+	m.z += mass * c.Z
+}
+
+func (m moments) center() Point {
+	if m.mass <= 0 {
+		return Point{}
+	}
+	return Point{X: m.x / m.mass, Y: m.y / m.mass, Z: m.z / m.mass}
+}
+
+func (s Space) center() Point {
+	return Point{
+		X: float64(s.Origin.X) + float64(s.Dim.Width)/2,
+		Y: float64(s.Origin.Y) + float64(s.Dim.Length)/2,
+		Z: float64(s.Origin.Z) + float64(s.Dim.Height)/2,
+	}
+}
+
+// CenterOfGravity devolve o centro de gravidade do conteúdo, com origem no canto interno (0, 0, 0) da caixa.
+// Produtos sem peso não contam quando outros têm; sem nenhum peso, o centro é o geométrico do volume ocupado.
+func (b *PackedBox) CenterOfGravity() Point {
+	if b.weightMoments.mass > 0 {
+		return b.weightMoments.center()
+	}
+	return b.volumeMoments.center()
+}
+
+// addMoments registra o volume ocupado por um produto nos momentos da caixa.
+func (b *PackedBox) addMoments(weight float64, placed Space) {
+	c := placed.center()
+	b.weightMoments.add(weight, c)
+	b.volumeMoments.add(float64(placed.Dim.Volume()), c)
+}
+
+// balanceOffset mede o quanto o centro de gravidade se afastaria do centro da caixa no plano do piso se o item
+// fosse alocado no volume: soma dos quadrados dos desvios em x e y, normalizados pela largura e pelo comprimento.
+func (b *PackedBox) balanceOffset(item Item, placed Space) float64 {
+	m := b.volumeMoments
+	mass := float64(placed.Dim.Volume())
+	if b.weightMoments.mass+item.Weight > 0 {
+		m, mass = b.weightMoments, item.Weight
+	}
+	m.add(mass, placed.center())
+
+	inner := b.BoxType.InnerDimensions()
+	c := m.center()
+	dx := (c.X - float64(inner.Width)/2) / float64(inner.Width)
+	dy := (c.Y - float64(inner.Length)/2) / float64(inner.Length)
+	return dx*dx + dy*dy
+}
@@ -0,0 +1,54 @@
+package packing
+
+import "testing"
+
+func TestPackedBox_CenterOfGravity(t *testing.T) {
+	b := newPackedBox(BoxType{ID: "Cubo", Height: 10, Width: 10, Length: 10}, Options{})
+	b.place(Item{ProductID: "A", Dim: Dimensions{Height: 2, Width: 2, Length: 2}, Weight: 3}, 0, Position{}, Dimensions{Height: 2, Width: 2, Length: 2})
+	b.place(Item{ProductID: "B", Dim: Dimensions{Height: 2, Width: 2, Length: 2}, Weight: 1}, 0, Position{X: 8}, Dimensions{Height: 2, Width: 2, Length: 2})
+
+	// A em (1, 1, 1) com 3 kg e B em (9, 1, 1) com 1 kg.
+	if got, want := b.CenterOfGravity(), (Point{X: 3, Y: 1, Z: 1}); got != want {
+		t.Fatalf("expected center of gravity %+v, got %+v", want, got)
+	}
+}
+
+func TestPackedBox_CenterOfGravityWithoutWeights(t *testing.T) {
+	b := newPackedBox(BoxType{ID: "Cubo", Height: 10, Width: 10, Length: 10}, Options{})
+	b.place(Item{ProductID: "A", Dim: Dimensions{Height: 2, Width: 6, Length: 2}}, 0, Position{}, Dimensions{Height: 2, Width: 6, Length: 2})
+	b.place(Item{ProductID: "B", Dim: Dimensions{Height: 2, Width: 2, Length: 2}}, 0, Position{X: 8}, Dimensions{Height: 2, Width: 2, Length: 2})
+
+	// Sem pesos, pondera pelo volume: A (24) centrado em x=3 e B (8) em x=9.
+	if got := b.CenterOfGravity(); got.X != 4.5 {
+		t.Fatalf("expected volume-weighted x=4.5, got %+v", got)
+	}
+}
+
+func TestPackOrder_BalancePrefersCenteredRotation(t *testing.T) {
+	boxes := []BoxType{{ID: "Cubo", Height: 10, Width: 10, Length: 10}}
+	items := func() []Item {
+		return []Item{{ProductID: "Pilar", Dim: Dimensions{Height: 10, Width: 2, Length: 2}, Weight: 5}}
+	}
+
+	plain, err := PackOrderWithOptions(items(), boxes, Options{AllowRotation: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	balanced, err := PackOrderWithOptions(items(), boxes, Options{AllowRotation: true, Balance: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Em pé no canto, o centro fica em (1, 1); deitado, um dos eixos passa pelo centro da caixa.
+	if got := plain.Boxes[0].Products[0].Rotation.Height; got != 10 {
+		t.Fatalf("expected unbalanced packing to keep the pillar upright, got height %d", got)
+	}
+	if got := balanced.Boxes[0].Products[0].Rotation.Height; got != 2 {
+		t.Fatalf("expected balanced packing to lay the pillar down, got height %d", got)
+	}
+	offset := func(p Point) float64 { return (p.X-5)*(p.X-5) + (p.Y-5)*(p.Y-5) }
+	plainCG, balancedCG := plain.Boxes[0].CenterOfGravity(), balanced.Boxes[0].CenterOfGravity()
+	if offset(balancedCG) >= offset(plainCG) {
+		t.Fatalf("expected balanced center %+v closer to the box center than %+v", balancedCG, plainCG)
+	}
+}
@@ -0,0 +1,127 @@
+package packing
+
+// blockFallbackMaxItems limita a verificação dos blocos: em pedidos com até esse número de itens, a estratégia
+// também roda unidade por unidade e fica com o melhor resultado (ver packUnits).
+const blockFallbackMaxItems = 512
+
+// runEnd devolve o fim da sequência de unidades de uma mesma linha do pedido (mesmo Index, expandidas de
+// quantidade) que começa em i; sem blocos, cada unidade é uma sequência. Como os itens são ordenados por volume,
+// produto e posição no pedido, as unidades de uma linha ficam adjacentes. Linhas distintas de um mesmo produto
+// não formam blocos.
+func (o Options) runEnd(items []Item, i int) int {
+	j := i + 1
+	if o.noBlocks {
+		return j
+	}
+	for j < len(items) && sameRun(items[j], items[i]) {
+		j++
+	}
+	return j
+}
+
+func sameRun(a, b Item) bool {
+	return a.Index == b.Index && a.ProductID == b.ProductID && sameItem(a, b)
+}
+
+// packUnits roda a estratégia alocando as unidades de cada linha em blocos. Blocos são rápidos para quantidades
+// grandes, mas não equivalem a alocar as unidades uma a uma e podem abrir mais caixas; por isso, em pedidos com
+// até blockFallbackMaxItems itens que formam blocos, a estratégia também roda unidade por unidade e prevalece o
+// resultado de menor custo (em empate, o com blocos).
+func packUnits(items []Item, boxTypes []BoxType, opts Options) (OrderPackingResult, error) {
+	res, err := opts.packer().Pack(items, boxTypes, opts)
+	if err != nil || opts.noBlocks || len(items) > blockFallbackMaxItems || !formsBlocks(items) {
+		return res, err
+	}
+
+	oneByOne := opts
+	oneByOne.noBlocks = true
+	alt, err := oneByOne.packer().Pack(items, boxTypes, oneByOne)
+	if err != nil {
+		return OrderPackingResult{}, err
+	}
+
+	var obj Objective = BoxCountObjective{}
+	if opts.Objective != nil {
+		obj = opts.Objective
+	}
+	if alt.Cost(obj) < res.Cost(obj)-costEpsilon {
+		return alt, nil
+	}
+	return res, nil
+}
+
+// formsBlocks informa se alguma linha do pedido tem mais de uma unidade.
+func formsBlocks(items []Item) bool {
+	for i := 1; i < len(items); i++ {
+		if sameRun(items[i], items[i-1]) {
+			return true
+		}
+	}
+	return false
+}
+
+// placeRun aloca na caixa, em blocos, o máximo possível de itens idênticos e devolve quantos foram alocados
+// (sempre um prefixo de run).
+func (b *PackedBox) placeRun(run []Item, allowRotation bool) int {
+	placed := 0
+	for placed < len(run) {
+		n := b.placeBlock(run[placed:], allowRotation)
+		if n == 0 {
+			break
+		}
+		placed += n
+	}
+	return placed
+}
+
+// placeBlock escolhe o espaço livre e a rotação de menor desperdício para o primeiro item e ocupa esse espaço
+// com uma grade retangular de itens idênticos (largura, depois comprimento, depois altura), limitada pela
+// quantidade disponível e pela carga máxima (um item por vez quando há limites de empilhamento ou, fora do piso,
+// apoio mínimo). Devolve quantos itens do início de run foram alocados.
+func (b *PackedBox) placeBlock(run []Item, allowRotation bool) int {
+	first := run[0]
+	pl, ok := b.findPlacement(first, allowRotation)
+	if !ok {
+		return 0
+	}
+
+	n := len(run)
+	if b.stackingChecked(first) {
+		// Com limites de carga, cada unidade passa pela verificação de empilhamento de findPlacement.
+		n = 1
+	}
+	if b.minSupport > 0 && b.freeSpaces[pl.spaceIndex].Origin.Z > 0 {
+		// O apoio só foi verificado para o primeiro item; os vizinhos da camada de baixo podem ficar sem base.
+		n = 1
+	}
+	for n > 1 && !b.BoxType.CanCarry(b.ContentWeight+float64(n)*first.Weight) {
+		n--
+	}
+
+	space := b.freeSpaces[pl.spaceIndex]
+	rot := pl.rot
+	nx := min(space.Dim.Width/rot.Width, n)
+	ny := min(space.Dim.Length/rot.Length, n/nx)
+	nz := min(space.Dim.Height/rot.Height, n/(nx*ny))
+
+	b.occupy(pl.spaceIndex, Space{
+		Origin: space.Origin,
+		Dim:    Dimensions{Height: nz * rot.Height, Width: nx * rot.Width, Length: ny * rot.Length},
+	})
+
+	k := 0
+	for z := 0; z < nz; z++ {
+		for y := 0; y < ny; y++ {
+			for x := 0; x < nx; x++ {
+				origin := Position{
+					X: space.Origin.X + x*rot.Width,
+					Y: space.Origin.Y + y*rot.Length,
+					Z: space.Origin.Z + z*rot.Height,
+				}
+				b.record(run[k], origin, rot)
+				k++
+			}
+		}
+	}
+	return k
+}
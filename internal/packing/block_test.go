@@ -0,0 +1,96 @@
+package packing
+
+import "testing"
+
+func TestPackOrder_IdenticalUnitsPlacedInBlocks(t *testing.T) {
+	boxes := []BoxType{{ID: "Cubo", Height: 10, Width: 10, Length: 10}}
+	items := make([]Item, 0, 20)
+	for i := 0; i < 20; i++ {
+		items = append(items, Item{ProductID: "Caneca", Dim: Dimensions{Height: 5, Width: 5, Length: 5}, Index: 0})
+	}
+
+	for _, packer := range []Packer{FirstFitDecreasing{}, BestFitDecreasing{}, LayerPacker{}} {
+		res, err := PackOrderWithOptions(append([]Item(nil), items...), boxes, Options{Packer: packer})
+		if err != nil {
+			t.Fatalf("%T: unexpected error: %v", packer, err)
+		}
+		if len(res.Boxes) != 3 {
+			t.Fatalf("%T: expected 3 boxes (8 + 8 + 4), got %d", packer, len(res.Boxes))
+		}
+
+		packed := 0
+		for _, b := range res.Boxes {
+			for i, p := range b.Products {
+				packed++
+				for _, q := range b.Products[i+1:] {
+					if overlaps(p, q) {
+						t.Fatalf("%T: units overlap at %+v and %+v", packer, p.Position, q.Position)
+					}
+				}
+			}
+		}
+		if packed != 20 {
+			t.Fatalf("%T: expected 20 units packed, got %d", packer, packed)
+		}
+	}
+}
+
+func TestPlaceBlock_RespectsPayload(t *testing.T) {
+	b := newPackedBox(BoxType{ID: "Cubo", Height: 10, Width: 10, Length: 10, MaxWeight: 3}, Options{})
+	run := make([]Item, 8)
+	for i := range run {
+		run[i] = Item{ProductID: "Peso", Dim: Dimensions{Height: 5, Width: 5, Length: 5}, Weight: 1}
+	}
+
+	if got := b.placeRun(run, false); got != 3 {
+		t.Fatalf("expected payload to limit the block to 3 units, got %d", got)
+	}
+	if b.ContentWeight != 3 {
+		t.Fatalf("expected content weight 3, got %v", b.ContentWeight)
+	}
+}
+
+// blockRegressionItems: quatro unidades de A e um B que cabem em uma caixa só quando as unidades de A são
+// alocadas uma a uma. sameLine faz as unidades de A virem de uma linha com quantidade (mesmo Index).
+func blockRegressionItems(sameLine bool) []Item {
+	items := make([]Item, 0, 5)
+	for i := 0; i < 4; i++ {
+		index := i
+		if sameLine {
+			index = 0
+		}
+		items = append(items, Item{ProductID: "A", Dim: Dimensions{Height: 14, Width: 34, Length: 25}, Index: index})
+	}
+	return append(items, Item{ProductID: "B", Dim: Dimensions{Height: 32, Width: 18, Length: 17}, Index: 4})
+}
+
+func TestPackOrder_BlocksNeverOpenMoreBoxesThanOneByOne(t *testing.T) {
+	oneByOne, err := PackOrderWithOptions(blockRegressionItems(false), AvailableBoxes(), Options{AllowRotation: true, noBlocks: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(oneByOne.Boxes) != 1 {
+		t.Fatalf("expected one-by-one placement to use 1 box, got %d", len(oneByOne.Boxes))
+	}
+
+	for name, sameLine := range map[string]bool{"linhas repetidas": false, "quantidade": true} {
+		res, err := PackOrder(blockRegressionItems(sameLine), AvailableBoxes(), true)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if len(res.Boxes) != len(oneByOne.Boxes) {
+			t.Fatalf("%s: expected %d box like one-by-one placement, got %d", name, len(oneByOne.Boxes), len(res.Boxes))
+		}
+	}
+}
+
+func TestRunEnd_OnlyGroupsUnitsOfTheSameLine(t *testing.T) {
+	items := blockRegressionItems(false)
+	if end := (Options{}).runEnd(items, 0); end != 1 {
+		t.Fatalf("expected separate lines of the same product not to form a block, got run end %d", end)
+	}
+	items = blockRegressionItems(true)
+	if end := (Options{}).runEnd(items, 0); end != 4 {
+		t.Fatalf("expected the 4 units of one line to form a block, got run end %d", end)
+	}
+}
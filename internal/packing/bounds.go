@@ -0,0 +1,328 @@
+package packing
+
+import (
+	"math"
+	"sort"
+)
+
+// LowerBound reúne limites inferiores para o número de caixas de um pedido. Todos valem para o catálogo inteiro
+// (qualquer mistura de tipos de caixa) e ignoram restrições de empilhamento e apoio, que só podem exigir mais caixas.
+type LowerBound struct {
+	// Volume é o volume total dos itens sobre o volume interno da maior caixa.
+	Volume int
+	// Weight é o peso total sobre a maior carga máxima; zero quando alguma caixa não tem limite de peso.
+	Weight int
+	// Incompatible é o tamanho de uma clique de incompatibilidade: itens que, dois a dois, não cabem juntos em
+	// nenhuma caixa e por isso exigem uma caixa cada. A clique é montada de forma gulosa (ver incompatibleBound),
+	// então o limite é válido, mas não necessariamente a maior clique.
+	Incompatible int
+	// Dimensional são os limites L1/L2 de Martello, Pisinger e Vigo: itens maiores que metade da caixa em duas
+	// dimensões não cabem lado a lado nelas e só podem ser empilhados ao longo da terceira (ver dimensionalBound).
+	Dimensional int
+	// Value é o maior dos limites acima ou, quando a busca exata comprova o ótimo, o número de caixas da solução.
+	Value int
+}
+
+// Gap é quantas caixas a solução usa acima do limite inferior.
+func (lb LowerBound) Gap(boxes int) int {
+	return max(0, boxes-lb.Value)
+}
+
+// lowerBound calcula os limites inferiores dos itens para o catálogo informado.
+func lowerBound(items []Item, boxTypes []BoxType, opts Options) LowerBound {
+	maxVolume, maxWeight := 0, 0.0
+	weightLimited := len(boxTypes) > 0
+	for _, bt := range boxTypes {
+		maxVolume = max(maxVolume, bt.Height*bt.Width*bt.Length)
+		if bt.MaxWeight <= 0 {
+			weightLimited = false
+		}
+		maxWeight = max(maxWeight, bt.MaxWeight)
+	}
+
+	totalVolume, totalWeight := 0, 0.0
+	for _, it := range items {
+		totalVolume += it.Dim.Volume()
+		totalWeight += it.Weight
+	}
+
+	lb := LowerBound{Volume: 1}
+	if maxVolume > 0 {
+		lb.Volume = max(lb.Volume, (totalVolume+maxVolume-1)/maxVolume)
+	}
+	if weightLimited && maxWeight > 0 {
+		lb.Weight = int(math.Ceil(totalWeight/maxWeight - costEpsilon))
+	}
+	lb.Incompatible = incompatibleBound(items, boxTypes, opts.AllowRotation)
+	lb.Dimensional = dimensionalBound(items, boxTypes, opts.AllowRotation)
+	lb.Value = max(lb.Volume, lb.Weight, lb.Incompatible, lb.Dimensional)
+	return lb
+}
+
+// itemKind agrupa unidades com a mesma forma, peso e orientação: para o limite, são intercambiáveis.
+type itemKind struct {
+	Dim         Dimensions
+	Weight      float64
+	Orientation Orientation
+}
+
+// incompatibleBound monta, de forma gulosa, conjuntos de itens em que nenhum par cabe junto em uma mesma caixa;
+// o tamanho de qualquer conjunto assim é um limite inferior válido. A gula roda em duas ordens (maior volume e
+// maior menor lado primeiro, que favorece itens "grossos" como cubos) e fica com o maior conjunto.
+func incompatibleBound(items []Item, boxTypes []BoxType, allowRotation bool) int {
+	counts := make(map[itemKind]int)
+	var kinds []Item
+	for _, it := range items {
+		k := kindOf(it)
+		if counts[k] == 0 {
+			kinds = append(kinds, it)
+		}
+		counts[k]++
+	}
+
+	byVolume := append([]Item(nil), kinds...)
+	sort.SliceStable(byVolume, func(i, j int) bool {
+		return byVolume[i].Dim.Volume() > byVolume[j].Dim.Volume()
+	})
+	bySide := append([]Item(nil), kinds...)
+	sort.SliceStable(bySide, func(i, j int) bool {
+		return bySide[i].Dim.minSide() > bySide[j].Dim.minSide()
+	})
+
+	return max(
+		greedyIncompatible(byVolume, counts, boxTypes, allowRotation),
+		greedyIncompatible(bySide, counts, boxTypes, allowRotation),
+	)
+}
+
+// greedyIncompatible percorre os tipos de item na ordem dada, mantendo os que não cabem com nenhum já escolhido.
+// Unidades idênticas são avaliadas uma vez: entram todas se duas delas não cabem juntas, senão só uma.
+func greedyIncompatible(kinds []Item, counts map[itemKind]int, boxTypes []BoxType, allowRotation bool) int {
+	var clique []Item
+	size := 0
+	for _, it := range kinds {
+		compatible := false
+		for _, c := range clique {
+			if canShareBox(it, c, boxTypes, allowRotation) {
+				compatible = true
+				break
+			}
+		}
+		if compatible {
+			continue
+		}
+
+		clique = append(clique, it)
+		if canShareBox(it, it, boxTypes, allowRotation) {
+			size++
+		} else {
+			size += counts[kindOf(it)]
+		}
+	}
+	return size
+}
+
+func kindOf(it Item) itemKind {
+	return itemKind{Dim: it.Dim, Weight: it.Weight, Orientation: it.Orientation}
+}
+
+func (d Dimensions) minSide() int {
+	return min(d.Height, d.Width, d.Length)
+}
+
+// canShareBox informa se os dois itens cabem juntos em algum tipo de caixa. Dois volumes sem sobreposição estão
+// sempre separados ao longo de algum eixo, então basta que as extensões somadas caibam em um eixo e cada item
+// caiba sozinho nos demais.
+func canShareBox(a, b Item, boxTypes []BoxType, allowRotation bool) bool {
+	for _, bt := range boxTypes {
+		if !bt.CanCarry(a.Weight + b.Weight) {
+			continue
+		}
+		box := bt.InnerDimensions()
+		for _, ra := range a.rotations(allowRotation) {
+			if !ra.FitsIn(box) {
+				continue
+			}
+			for _, rb := range b.rotations(allowRotation) {
+				if !rb.FitsIn(box) {
+					continue
+				}
+				if ra.Width+rb.Width <= box.Width || ra.Length+rb.Length <= box.Length || ra.Height+rb.Height <= box.Height {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// extent guarda, para um tipo de item, a menor extensão que ele pode ter em cada eixo (largura, comprimento e
+// altura) entre as rotações que cabem em alguma caixa, o volume e quantas unidades há dele.
+type extent struct {
+	side   [3]int
+	volume int
+	count  int
+}
+
+// dimensionalBound calcula os limites L1 e L2 de Martello, Pisinger e Vigo sobre uma caixa virtual com a maior
+// largura, o maior comprimento e a maior altura do catálogo, que contém qualquer caixa real. Para cada par de eixos
+// (a, b), com c o terceiro:
+//
+//   - L1: itens maiores que metade da caixa em a e em b não cabem lado a lado em nenhum dos dois, então dois deles
+//     na mesma caixa ficam sempre um após o outro ao longo de c; o limite de Martello e Toth para bin packing em
+//     uma dimensão, aplicado às extensões em c, vale para eles;
+//   - L2: para limiares p ≤ a/2 e q ≤ b/2, os itens maiores que a-p e b-q (K_v) e os demais com pelo menos p e q
+//     (K_s) também só se separam ao longo de c, então os K_s ocupam no máximo a fatia de c não usada pelos K_v.
+//
+// Com rotação, cada item entra nas classes pela menor extensão em cada eixo, o que mantém os limites válidos.
+func dimensionalBound(items []Item, boxTypes []BoxType, allowRotation bool) int {
+	var bin [3]int
+	for _, bt := range boxTypes {
+		inner := bt.InnerDimensions()
+		bin = [3]int{max(bin[0], inner.Width), max(bin[1], inner.Length), max(bin[2], inner.Height)}
+	}
+	if bin[0] == 0 || bin[1] == 0 || bin[2] == 0 {
+		return 0
+	}
+
+	index := make(map[itemKind]int)
+	var extents []extent
+	for _, it := range items {
+		k := kindOf(it)
+		if i, ok := index[k]; ok {
+			extents[i].count++
+			continue
+		}
+		e, ok := minExtent(it, boxTypes, allowRotation)
+		if !ok {
+			continue
+		}
+		index[k] = len(extents)
+		extents = append(extents, e)
+	}
+
+	best := 0
+	for c := range 3 {
+		a, b := (c+1)%3, (c+2)%3
+		best = max(best, dimensionalL1(extents, bin, a, b, c), dimensionalL2(extents, bin, a, b, c))
+	}
+	return best
+}
+
+// minExtent devolve as menores extensões do item em cada eixo entre as rotações permitidas que cabem em alguma
+// caixa; ok é falso quando nenhuma cabe.
+func minExtent(it Item, boxTypes []BoxType, allowRotation bool) (extent, bool) {
+	e := extent{volume: it.Dim.Volume(), count: 1}
+	found := false
+	for _, rot := range it.rotations(allowRotation) {
+		fits := false
+		for _, bt := range boxTypes {
+			if rot.FitsIn(bt.InnerDimensions()) {
+				fits = true
+				break
+			}
+		}
+		if !fits {
+			continue
+		}
+		side := [3]int{rot.Width, rot.Length, rot.Height}
+		if !found {
+			e.side, found = side, true
+			continue
+		}
+		for i := range side {
+			e.side[i] = min(e.side[i], side[i])
+		}
+	}
+	return e, found
+}
+
+// dimensionalL1 aplica o limite de Martello e Toth aos itens maiores que metade da caixa nos eixos a e b,
+// usando a extensão em c como tamanho.
+func dimensionalL1(extents []extent, bin [3]int, a, b, c int) int {
+	var sizes []extent
+	for _, e := range extents {
+		if 2*e.side[a] > bin[a] && 2*e.side[b] > bin[b] {
+			sizes = append(sizes, e)
+		}
+	}
+	if len(sizes) == 0 {
+		return 0
+	}
+
+	capacity := bin[c]
+	best := 0
+	for _, alpha := range thresholds(sizes, c, capacity) {
+		// Acima de capacity-alpha nada mais cabe junto; acima da metade, no máximo um por caixa; itens entre
+		// alpha e a metade só cabem no que sobra das caixas do segundo grupo ou em caixas novas.
+		large, medium, mediumSize, smallSize := 0, 0, 0, 0
+		for _, e := range sizes {
+			s := e.side[c]
+			switch {
+			case s > capacity-alpha:
+				large += e.count
+			case 2*s > capacity:
+				medium += e.count
+				mediumSize += e.count * s
+			case s >= alpha:
+				smallSize += e.count * s
+			}
+		}
+		extra := max(0, ceilDiv(smallSize-(medium*capacity-mediumSize), capacity))
+		best = max(best, large+medium+extra)
+	}
+	return best
+}
+
+// dimensionalL2 combina, para cada par de limiares (p, q), a extensão em c dos itens K_v e o volume dos itens
+// K_s: numa caixa, os K_s só ocupam a fatia de c não usada pelos K_v.
+func dimensionalL2(extents []extent, bin [3]int, a, b, c int) int {
+	binVolume := bin[0] * bin[1] * bin[2]
+	face := bin[a] * bin[b]
+	best := 0
+	for _, p := range thresholds(extents, a, bin[a]) {
+		for _, q := range thresholds(extents, b, bin[b]) {
+			if p == 0 || q == 0 {
+				continue
+			}
+			stacked, volume := 0, 0
+			for _, e := range extents {
+				switch {
+				case e.side[a] > bin[a]-p && e.side[b] > bin[b]-q:
+					stacked += e.count * e.side[c]
+				case e.side[a] >= p && e.side[b] >= q:
+					volume += e.count * e.volume
+				}
+			}
+			if stacked > 0 {
+				best = max(best, ceilDiv(volume+face*stacked, binVolume))
+			}
+		}
+	}
+	return best
+}
+
+// thresholds devolve os limiares candidatos no eixo: zero, metade da capacidade e as extensões de até metade
+// da capacidade. Extensões são inteiras, então há no máximo capacity/2+1 candidatos.
+func thresholds(extents []extent, axis, capacity int) []int {
+	seen := map[int]bool{0: true, capacity / 2: true}
+	values := []int{0}
+	if capacity/2 > 0 {
+		values = append(values, capacity/2)
+	}
+	for _, e := range extents {
+		s := e.side[axis]
+		if 2*s <= capacity && !seen[s] {
+			seen[s] = true
+			values = append(values, s)
+		}
+	}
+	return values
+}
+
+func ceilDiv(a, b int) int {
+	if a <= 0 {
+		return 0
+	}
+	return (a + b - 1) / b
+}
@@ -0,0 +1,89 @@
+package packing
+
+import "testing"
+
+func TestLowerBound_IncompatibleItems(t *testing.T) {
+	boxes := []BoxType{{ID: "Cubo", Height: 10, Width: 10, Length: 10}}
+	cube := func(i int) Item {
+		return Item{ProductID: "Cubo6", Dim: Dimensions{Height: 6, Width: 6, Length: 6}, Index: i}
+	}
+
+	// Três cubos de 6 não cabem dois a dois no cubo de 10, embora somem só 648 de volume.
+	lb := lowerBound([]Item{cube(0), cube(1), cube(2)}, boxes, Options{AllowRotation: true})
+	if lb.Volume != 1 || lb.Incompatible != 3 || lb.Value != 3 {
+		t.Fatalf("expected volume bound 1 and incompatibility bound 3, got %+v", lb)
+	}
+
+	// Uma placa de 4 cabe ao lado de um cubo, mas não de dois.
+	plate := Item{ProductID: "Placa", Dim: Dimensions{Height: 4, Width: 10, Length: 10}, Index: 3}
+	lb = lowerBound([]Item{cube(0), cube(1), plate}, boxes, Options{AllowRotation: true})
+	if lb.Incompatible != 2 || lb.Value != 2 {
+		t.Fatalf("expected incompatibility bound 2, got %+v", lb)
+	}
+}
+
+func TestLowerBound_Weight(t *testing.T) {
+	boxes := []BoxType{{ID: "Cubo", Height: 10, Width: 10, Length: 10, MaxWeight: 10}}
+	items := []Item{
+		{ProductID: "A", Dim: Dimensions{Height: 1, Width: 1, Length: 1}, Weight: 6, Index: 0},
+		{ProductID: "B", Dim: Dimensions{Height: 1, Width: 1, Length: 1}, Weight: 6, Index: 1},
+	}
+
+	lb := lowerBound(items, boxes, Options{})
+	if lb.Weight != 2 || lb.Incompatible != 2 || lb.Value != 2 {
+		t.Fatalf("expected weight and incompatibility bounds of 2, got %+v", lb)
+	}
+}
+
+func TestPackOrder_ReportsLowerBoundAndGap(t *testing.T) {
+	boxes := []BoxType{{ID: "Cubo", Height: 10, Width: 10, Length: 10}}
+
+	res, err := PackOrderWithOptions(exactItems(), boxes, Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.LowerBound.Value != 1 || res.LowerBound.Gap(len(res.Boxes)) != 1 {
+		t.Fatalf("expected bound 1 and gap 1 for the heuristic, got %+v with %d boxes", res.LowerBound, len(res.Boxes))
+	}
+}
+
+func TestLowerBound_Dimensional(t *testing.T) {
+	boxes := []BoxType{{ID: "Cubo", Height: 10, Width: 10, Length: 10}}
+	plate := func(i int) Item {
+		return Item{ProductID: "Placa", Dim: Dimensions{Height: 2, Width: 6, Length: 6}, Index: i}
+	}
+	plates := []Item{plate(0), plate(1), plate(2), plate(3), plate(4), plate(5)}
+
+	// Sem rotação, placas mais largas e compridas que meia caixa só se empilham: 6 de 2 cm não cabem em 10 cm.
+	lb := lowerBound(plates, boxes, Options{})
+	if lb.Volume != 1 || lb.Incompatible != 1 || lb.Dimensional != 2 || lb.Value != 2 {
+		t.Fatalf("expected dimensional bound 2 above volume and incompatibility bounds of 1, got %+v", lb)
+	}
+
+	// Com rotação, as placas em pé cabem lado a lado e todas vão em uma caixa; nenhuma entra nas classes.
+	lb = lowerBound(plates, boxes, Options{AllowRotation: true})
+	if lb.Dimensional != 0 || lb.Value != 1 {
+		t.Fatalf("expected no dimensional bound with rotation, got %+v", lb)
+	}
+	res, err := PackOrder(plates, boxes, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Boxes) != 1 {
+		t.Fatalf("expected 1 box with rotation, got %d", len(res.Boxes))
+	}
+}
+
+func TestLowerBound_DimensionalVolumeAboveStackedItems(t *testing.T) {
+	boxes := []BoxType{{ID: "Cubo", Height: 10, Width: 10, Length: 10}}
+	// A placa 6x6 ocupa 3 cm de altura que nenhuma coluna 5x5 pode usar: sobram 700 de volume para 875.
+	items := []Item{{ProductID: "Placa", Dim: Dimensions{Height: 3, Width: 6, Length: 6}, Index: 0}}
+	for i := 1; i <= 5; i++ {
+		items = append(items, Item{ProductID: "Coluna", Dim: Dimensions{Height: 7, Width: 5, Length: 5}, Index: i})
+	}
+
+	lb := lowerBound(items, boxes, Options{})
+	if lb.Volume != 1 || lb.Incompatible != 1 || lb.Dimensional != 2 || lb.Value != 2 {
+		t.Fatalf("expected dimensional bound 2 above volume and incompatibility bounds of 1, got %+v", lb)
+	}
+}
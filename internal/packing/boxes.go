@@ -1,10 +1,40 @@
 package packing
 
 type BoxType struct {
-	ID          string
-	Height      int
-	Width       int
-	Length      int
+	ID string
+	// Height, Width e Length são as dimensões internas: o espaço útil onde os produtos são alocados.
+	Height int
+	Width  int
+	Length int
+	// WallThickness é a espessura da parede; sem Outer, as dimensões externas são as internas + 2× a espessura.
+	WallThickness int
+	// Outer são as dimensões externas medidas, usadas no frete e na etiqueta; zero deriva de WallThickness.
+	Outer Dimensions
+	// MaxWeight é a carga máxima de conteúdo suportada pela caixa; zero significa sem limite.
+	MaxWeight float64
+	// TareWeight é o peso da caixa vazia, somado ao conteúdo no peso bruto.
+	TareWeight float64
+	// UnitCost é o preço da caixa vazia, usado pelo CostModel.
+	UnitCost float64
+}
+
+// CanCarry informa se a caixa suporta o peso de conteúdo informado.
+func (bt BoxType) CanCarry(weight float64) bool {
+	return bt.MaxWeight <= 0 || weight <= bt.MaxWeight
+}
+
+// InnerDimensions é o espaço útil da caixa.
+func (bt BoxType) InnerDimensions() Dimensions {
+	return Dimensions{Height: bt.Height, Width: bt.Width, Length: bt.Length}
+}
+
+// OuterDimensions é o tamanho da caixa para transporte: Outer quando informado, senão as internas mais as paredes.
+func (bt BoxType) OuterDimensions() Dimensions {
+	if bt.Outer != (Dimensions{}) {
+		return bt.Outer
+	}
+	wall := 2 * bt.WallThickness
+	return Dimensions{Height: bt.Height + wall, Width: bt.Width + wall, Length: bt.Length + wall}
 }
 
 func AvailableBoxes() []BoxType {
@@ -0,0 +1,40 @@
+package packing
+
+// withClearance devolve os itens com a folga de proteção já somada às dimensões: cada item passa a ocupar
+// Dim + 2 × Clearance em cada eixo, tanto no teste de encaixe quanto na divisão dos espaços livres.
+// Trabalha sobre uma cópia para que reempacotar a mesma entrada não some a folga duas vezes.
+func withClearance(items []Item) []Item {
+	var padded []Item
+	for i, it := range items {
+		if it.Clearance <= 0 {
+			continue
+		}
+		if padded == nil {
+			padded = append([]Item(nil), items...)
+		}
+		c := 2 * it.Clearance
+		padded[i].Dim = Dimensions{Height: it.Dim.Height + c, Width: it.Dim.Width + c, Length: it.Dim.Length + c}
+	}
+
+	if padded == nil {
+		return items
+	}
+	return padded
+}
+
+// ProductPosition é a origem do produto em si dentro da caixa, descontada a folga (Position inclui a folga).
+func (p packedProduct) ProductPosition() Position {
+	c := p.item.Clearance
+	return Position{X: p.Position.X + c, Y: p.Position.Y + c, Z: p.Position.Z + c}
+}
+
+// ProductDimensions é a orientação usada para o produto em si, sem a folga (Rotation inclui a folga).
+func (p packedProduct) ProductDimensions() Dimensions {
+	c := 2 * p.item.Clearance
+	return Dimensions{Height: p.Rotation.Height - c, Width: p.Rotation.Width - c, Length: p.Rotation.Length - c}
+}
+
+// Clearance é a folga aplicada em cada face do produto.
+func (p packedProduct) Clearance() int {
+	return p.item.Clearance
+}
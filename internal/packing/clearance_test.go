@@ -0,0 +1,33 @@
+package packing
+
+import "testing"
+
+func TestPackOrder_ClearancePadsFootprint(t *testing.T) {
+	boxes := []BoxType{
+		{ID: "Justa", Height: 10, Width: 10, Length: 10},
+		{ID: "Folgada", Height: 14, Width: 14, Length: 14},
+	}
+	items := []Item{{ProductID: "Vaso", Dim: Dimensions{Height: 10, Width: 10, Length: 10}, Clearance: 2, Index: 0}}
+
+	res, err := PackOrderWithOptions(items, boxes, Options{AllowRotation: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := res.Boxes[0].BoxType.ID; got != "Folgada" {
+		t.Fatalf("expected padded item to need Folgada, got %s", got)
+	}
+
+	p := res.Boxes[0].Products[0]
+	if p.Rotation != (Dimensions{Height: 14, Width: 14, Length: 14}) {
+		t.Fatalf("expected padded footprint 14x14x14, got %+v", p.Rotation)
+	}
+	if p.ProductDimensions() != (Dimensions{Height: 10, Width: 10, Length: 10}) {
+		t.Fatalf("expected real dimensions 10x10x10, got %+v", p.ProductDimensions())
+	}
+	if p.ProductPosition() != (Position{X: 2, Y: 2, Z: 2}) {
+		t.Fatalf("expected product offset by clearance, got %+v", p.ProductPosition())
+	}
+	if items[0].Dim != (Dimensions{Height: 10, Width: 10, Length: 10}) {
+		t.Fatalf("expected caller items untouched, got %+v", items[0].Dim)
+	}
+}
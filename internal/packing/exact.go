@@ -0,0 +1,348 @@
+package packing
+
+// exactNodeLimit limita as tentativas de posicionamento da busca exata em um pedido.
+// Ao estourar, o resultado heurístico é mantido sem prova de ótimo.
+const exactNodeLimit = 500_000
+
+// exactCancelCheck é o intervalo, em tentativas, entre verificações de cancelamento da busca exata.
+const exactCancelCheck = 1024
+
+// maxExactItems é o teto técnico da busca exata (conjuntos de itens são representados em uma máscara uint64).
+const maxExactItems = 64
+
+// exactApplies informa se a busca exata deve rodar: pedido pequeno, objetivo de número de caixas e
+// nenhuma restrição que ela não modela (limites de empilhamento e apoio mínimo).
+func exactApplies(items []Item, opts Options) bool {
+	if opts.MinSupportRatio > 0 || opts.ExactMaxItems <= 0 || len(items) > opts.ExactMaxItems || len(items) > maxExactItems {
+		return false
+	}
+	for _, it := range items {
+		if it.loadLimited() {
+			return false
+		}
+	}
+	switch opts.Objective.(type) {
+	case nil, BoxCountObjective:
+		return true
+	}
+	return false
+}
+
+// packExact procura, por branch-and-bound, uma solução com menos caixas que a heurística.
+// Para cada k entre o limite inferior e o resultado heurístico, tenta distribuir os itens em k caixas;
+// a viabilidade de cada caixa é verificada de forma exata (ver fitsExactly). Se nenhum k menor for viável,
+// a solução heurística é ótima. Se o orçamento de nós estourar, a heurística é mantida sem prova.
+func packExact(items []Item, boxTypes []BoxType, opts Options, heuristic OrderPackingResult, lb int) OrderPackingResult {
+	s := &exactSearch{
+		items:    items,
+		boxTypes: boxTypes,
+		opts:     opts,
+		fitMemo:  make(map[fitKey]*PackedBox),
+		assign:   make([]int, len(items)),
+		suffix:   make([]int, len(items)+1),
+	}
+	for i := len(items) - 1; i >= 0; i-- {
+		s.suffix[i] = s.suffix[i+1] + items[i].Dim.Volume()
+	}
+	for _, bt := range boxTypes {
+		s.maxVolume = max(s.maxVolume, bt.Height*bt.Width*bt.Length)
+	}
+
+	for k := lb; k < len(heuristic.Boxes); k++ {
+		s.bins = s.bins[:0]
+		if s.assignItem(0, k) {
+			return s.result()
+		}
+		if s.aborted {
+			return heuristic
+		}
+	}
+
+	heuristic.Optimal = true
+	return heuristic
+}
+
+type fitKey struct {
+	box  int
+	mask uint64
+}
+
+type exactBin struct {
+	box    int
+	mask   uint64
+	volume int
+}
+
+type exactSearch struct {
+	items     []Item
+	boxTypes  []BoxType
+	opts      Options
+	maxVolume int
+	suffix    []int // volume dos itens a partir do índice i, para poda
+
+	nodes   int
+	aborted bool
+	fitMemo map[fitKey]*PackedBox // nil registra combinação inviável
+
+	assign   []int
+	bins     []exactBin
+	solution []exactBin
+}
+
+func (s *exactSearch) result() OrderPackingResult {
+	boxes := make([]PackedBox, 0, len(s.solution))
+	for _, bin := range s.solution {
+		boxes = append(boxes, *s.fitMemo[fitKey{box: bin.box, mask: bin.mask}])
+	}
+	return OrderPackingResult{Boxes: boxes, Optimal: true}
+}
+
+// assignItem distribui o item i (e os seguintes) em no máximo k caixas.
+func (s *exactSearch) assignItem(i, k int) bool {
+	if s.aborted {
+		return false
+	}
+	if i == len(s.items) {
+		s.solution = append(s.solution[:0], s.bins...)
+		return true
+	}
+
+	// Poda por volume: o restante precisa caber no espaço livre das caixas abertas mais as que ainda podem abrir.
+	free := (k - len(s.bins)) * s.maxVolume
+	for _, bin := range s.bins {
+		bt := s.boxTypes[bin.box]
+		free += bt.Height*bt.Width*bt.Length - bin.volume
+	}
+	if s.suffix[i] > free {
+		return false
+	}
+
+	it := s.items[i]
+	bit := uint64(1) << i
+
+	// Itens idênticos são intercambiáveis: o item i nunca vai para uma caixa anterior à do item idêntico anterior.
+	first := 0
+	if i > 0 && sameItem(it, s.items[i-1]) {
+		first = s.assign[i-1]
+	}
+
+	for j := first; j < len(s.bins); j++ {
+		bin := s.bins[j]
+		if s.fit(bin.box, bin.mask|bit) == nil {
+			continue
+		}
+		s.bins[j] = exactBin{box: bin.box, mask: bin.mask | bit, volume: bin.volume + it.Dim.Volume()}
+		s.assign[i] = j
+		if s.assignItem(i+1, k) {
+			return true
+		}
+		s.bins[j] = bin
+	}
+
+	if len(s.bins) < k {
+		for t := range s.boxTypes {
+			if s.fit(t, bit) == nil {
+				continue
+			}
+			s.bins = append(s.bins, exactBin{box: t, mask: bit, volume: it.Dim.Volume()})
+			s.assign[i] = len(s.bins) - 1
+			if s.assignItem(i+1, k) {
+				return true
+			}
+			s.bins = s.bins[:len(s.bins)-1]
+		}
+	}
+
+	return false
+}
+
+func sameItem(a, b Item) bool {
+	return a.Dim == b.Dim && a.Weight == b.Weight && a.Orientation == b.Orientation &&
+		a.Fragile == b.Fragile && a.MaxLoadOnTop == b.MaxLoadOnTop
+}
+
+// fit devolve a caixa montada com os itens da máscara, ou nil se eles não cabem juntos no tipo de caixa.
+func (s *exactSearch) fit(box int, mask uint64) *PackedBox {
+	key := fitKey{box: box, mask: mask}
+	if b, ok := s.fitMemo[key]; ok {
+		return b
+	}
+
+	b := s.computeFit(box, mask)
+	s.fitMemo[key] = b
+	return b
+}
+
+func (s *exactSearch) computeFit(box int, mask uint64) *PackedBox {
+	bt := s.boxTypes[box]
+
+	selected := make([]Item, 0, len(s.items))
+	volume, weight := 0, 0.0
+	for i, it := range s.items {
+		if mask&(uint64(1)<<i) != 0 {
+			selected = append(selected, it)
+			volume += it.Dim.Volume()
+			weight += it.Weight
+		}
+	}
+	if volume > bt.Height*bt.Width*bt.Length || !bt.CanCarry(weight) {
+		return nil
+	}
+
+	// Atalho: se a heurística com espaços maximais já acomoda o conjunto, não é preciso buscar.
+	quick := Options{AllowRotation: s.opts.AllowRotation, FreeSpace: MaximalSpaces}
+	if b, ok := packIntoSingleBox(selected, bt, quick); ok {
+		return &b
+	}
+
+	placed, ok := s.fitsExactly(selected, bt)
+	if !ok {
+		return nil
+	}
+
+	b := newPackedBox(bt, Options{FreeSpace: MaximalSpaces})
+	for i, it := range selected {
+		b.place(it, -1, placed[i].Origin, placed[i].Dim)
+	}
+	return &b
+}
+
+// fitsExactly decide se os itens cabem juntos na caixa testando posições dos "normal patterns":
+// existe sempre uma arrumação viável (se houver alguma) em que cada coordenada é a soma de extensões de outros itens
+// no mesmo eixo, então basta enumerar essas posições com todas as rotações permitidas.
+func (s *exactSearch) fitsExactly(items []Item, bt BoxType) ([]Space, bool) {
+	box := Dimensions{Height: bt.Height, Width: bt.Width, Length: bt.Length}
+
+	rots := make([][]Dimensions, len(items))
+	for i, it := range items {
+		for _, r := range it.rotations(s.opts.AllowRotation) {
+			if r.FitsIn(box) {
+				rots[i] = append(rots[i], r)
+			}
+		}
+		if len(rots[i]) == 0 {
+			return nil, false
+		}
+	}
+
+	bin := oneBinSearch{
+		search: s,
+		box:    box,
+		items:  items,
+		rots:   rots,
+		xs:     normalPatterns(rots, box.Width, func(d Dimensions) int { return d.Width }),
+		ys:     normalPatterns(rots, box.Length, func(d Dimensions) int { return d.Length }),
+		zs:     normalPatterns(rots, box.Height, func(d Dimensions) int { return d.Height }),
+	}
+	if !bin.place(0) {
+		return nil, false
+	}
+	return bin.placed, true
+}
+
+// normalPatterns devolve, em ordem crescente, as somas possíveis das extensões dos itens em um eixo (limitadas à caixa).
+func normalPatterns(rots [][]Dimensions, limit int, extent func(Dimensions) int) []int {
+	reachable := make([]bool, limit+1)
+	reachable[0] = true
+
+	for _, options := range rots {
+		next := append([]bool(nil), reachable...)
+		for sum, ok := range reachable {
+			if !ok {
+				continue
+			}
+			for _, r := range options {
+				if e := sum + extent(r); e <= limit {
+					next[e] = true
+				}
+			}
+		}
+		reachable = next
+	}
+
+	patterns := make([]int, 0)
+	for sum, ok := range reachable {
+		if ok && sum < limit {
+			patterns = append(patterns, sum)
+		}
+	}
+	return patterns
+}
+
+type oneBinSearch struct {
+	search     *exactSearch
+	box        Dimensions
+	items      []Item
+	rots       [][]Dimensions
+	xs, ys, zs []int
+	placed     []Space
+}
+
+func (o *oneBinSearch) place(m int) bool {
+	if m == len(o.items) {
+		return true
+	}
+
+	s := o.search
+	for _, rot := range o.rots[m] {
+		for _, z := range o.zs {
+			if z+rot.Height > o.box.Height {
+				break
+			}
+			for _, y := range o.ys {
+				if y+rot.Length > o.box.Length {
+					break
+				}
+				for _, x := range o.xs {
+					if x+rot.Width > o.box.Width {
+						break
+					}
+
+					s.nodes++
+					if s.nodes > exactNodeLimit || (s.nodes%exactCancelCheck == 0 && s.opts.canceled() != nil) {
+						s.aborted = true
+						return false
+					}
+
+					cand := Space{Origin: Position{X: x, Y: y, Z: z}, Dim: rot}
+					// Itens idênticos são intercambiáveis: exige posições em ordem crescente (z, y, x).
+					if m > 0 && sameItem(o.items[m], o.items[m-1]) && !positionLess(o.placed[m-1].Origin, cand.Origin) {
+						continue
+					}
+					if o.overlapsPlaced(cand) {
+						continue
+					}
+
+					o.placed = append(o.placed, cand)
+					if o.place(m + 1) {
+						return true
+					}
+					o.placed = o.placed[:m]
+					if s.aborted {
+						return false
+					}
+				}
+			}
+		}
+	}
+	return false
+}
+
+func (o *oneBinSearch) overlapsPlaced(cand Space) bool {
+	for _, p := range o.placed {
+		if p.intersects(cand) {
+			return true
+		}
+	}
+	return false
+}
+
+func positionLess(a, b Position) bool {
+	if a.Z != b.Z {
+		return a.Z < b.Z
+	}
+	if a.Y != b.Y {
+		return a.Y < b.Y
+	}
+	return a.X < b.X
+}
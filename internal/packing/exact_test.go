@@ -0,0 +1,93 @@
+package packing
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func exactItems() []Item {
+	return []Item{
+		{ProductID: "A", Dim: Dimensions{Height: 10, Width: 4, Length: 9}, Index: 0},
+		{ProductID: "B", Dim: Dimensions{Height: 1, Width: 5, Length: 7}, Index: 1},
+		{ProductID: "C", Dim: Dimensions{Height: 4, Width: 4, Length: 6}, Index: 2},
+	}
+}
+
+func TestPackOrder_ExactModeFindsFewerBoxes(t *testing.T) {
+	boxes := []BoxType{{ID: "Cubo", Height: 10, Width: 10, Length: 10}}
+
+	heuristic, err := PackOrderWithOptions(exactItems(), boxes, Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(heuristic.Boxes) != 2 || heuristic.Optimal {
+		t.Fatalf("expected heuristic to open 2 boxes without proof, got %d (optimal=%v)", len(heuristic.Boxes), heuristic.Optimal)
+	}
+
+	exact, err := PackOrderWithOptions(exactItems(), boxes, Options{ExactMaxItems: 8})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(exact.Boxes) != 1 || !exact.Optimal {
+		t.Fatalf("expected exact mode to prove 1 box, got %d (optimal=%v)", len(exact.Boxes), exact.Optimal)
+	}
+
+	products := exact.Boxes[0].Products
+	if len(products) != 3 {
+		t.Fatalf("expected 3 products in the box, got %d", len(products))
+	}
+	for i := range products {
+		p := products[i]
+		end := Position{X: p.Position.X + p.Rotation.Width, Y: p.Position.Y + p.Rotation.Length, Z: p.Position.Z + p.Rotation.Height}
+		if end.X > 10 || end.Y > 10 || end.Z > 10 {
+			t.Fatalf("product %s exceeds box bounds: %+v", p.ID, p)
+		}
+		for j := i + 1; j < len(products); j++ {
+			if overlaps(p, products[j]) {
+				t.Fatalf("products %s and %s overlap", p.ID, products[j].ID)
+			}
+		}
+	}
+}
+
+func TestPackOrder_ExactModeProvesHeuristicOptimal(t *testing.T) {
+	boxes := []BoxType{{ID: "Cubo", Height: 10, Width: 10, Length: 10}}
+	items := []Item{
+		{ProductID: "A", Dim: Dimensions{Height: 6, Width: 6, Length: 6}, Index: 0},
+		{ProductID: "B", Dim: Dimensions{Height: 6, Width: 6, Length: 6}, Index: 1},
+	}
+
+	res, err := PackOrderWithOptions(items, boxes, Options{AllowRotation: true, ExactMaxItems: 8})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Boxes) != 2 || !res.Optimal {
+		t.Fatalf("expected 2 boxes proven optimal, got %d (optimal=%v)", len(res.Boxes), res.Optimal)
+	}
+}
+
+func TestPackOrder_ExactModeSkippedAboveThreshold(t *testing.T) {
+	boxes := []BoxType{{ID: "Cubo", Height: 10, Width: 10, Length: 10}}
+
+	res, err := PackOrderWithOptions(exactItems(), boxes, Options{ExactMaxItems: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Boxes) != 2 || res.Optimal {
+		t.Fatalf("expected heuristic result above threshold, got %d (optimal=%v)", len(res.Boxes), res.Optimal)
+	}
+}
+
+func TestPackOrderContext_CanceledStopsEveryStrategy(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	boxes := []BoxType{{ID: "Cubo", Height: 10, Width: 10, Length: 10}}
+	for _, packer := range []Packer{FirstFitDecreasing{}, BestFitDecreasing{}, LayerPacker{}, BestOf{}} {
+		_, err := PackOrderContext(ctx, exactItems(), boxes, Options{Packer: packer, ExactMaxItems: 8})
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("%T: expected context.Canceled, got %v", packer, err)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package packing
+
+import "sort"
+
+// FreeSpaceStrategy define como a caixa atualiza seus espaços livres após cada alocação.
+type FreeSpaceStrategy string
+
+const (
+	// GuillotineSplit divide o espaço usado em até 3 fatias disjuntas (comportamento original).
+	GuillotineSplit FreeSpaceStrategy = "guillotine"
+	// MaximalSpaces mantém apenas espaços livres maximais, que podem se sobrepor e não desperdiçam volume entre fatias.
+	MaximalSpaces FreeSpaceStrategy = "maximal_spaces"
+)
+
+// end devolve o canto oposto à origem (exclusivo) do espaço.
+func (s Space) end() Position {
+	return Position{
+		X: s.Origin.X + s.Dim.Width,
+		Y: s.Origin.Y + s.Dim.Length,
+		Z: s.Origin.Z + s.Dim.Height,
+	}
+}
+
+func (s Space) intersects(o Space) bool {
+	se, oe := s.end(), o.end()
+	return s.Origin.X < oe.X && o.Origin.X < se.X &&
+		s.Origin.Y < oe.Y && o.Origin.Y < se.Y &&
+		s.Origin.Z < oe.Z && o.Origin.Z < se.Z
+}
+
+func (s Space) contains(o Space) bool {
+	se, oe := s.end(), o.end()
+	return s.Origin.X <= o.Origin.X && s.Origin.Y <= o.Origin.Y && s.Origin.Z <= o.Origin.Z &&
+		oe.X <= se.X && oe.Y <= se.Y && oe.Z <= se.Z
+}
+
+// splitGuillotine remove o espaço usado e gera até 3 sobras disjuntas (lateral, frontal e superior).
+// Estratégia tipo guilhotina, simples e determinística: mantém layout previsível para encaixes futuros.
+func splitGuillotine(spaces []Space, used int, placed Space) []Space {
+	space := spaces[used]
+	origin := space.Origin
+	rot := placed.Dim
+
+	spaces = append(spaces[:used], spaces[used+1:]...)
+
+	// 1) Slice lateral (largura restante)
+	if space.Dim.Width-rot.Width > 0 {
+		spaces = append(spaces, Space{
+			Origin: Position{X: origin.X + rot.Width, Y: origin.Y, Z: origin.Z},
+			Dim: Dimensions{
+				Height: space.Dim.Height,
+				Width:  space.Dim.Width - rot.Width,
+				Length: space.Dim.Length,
+			},
+		})
+	}
+	// 2) Slice frontal (comprimento restante) dentro da largura do item
+	if space.Dim.Length-rot.Length > 0 {
+		spaces = append(spaces, Space{
+			Origin: Position{X: origin.X, Y: origin.Y + rot.Length, Z: origin.Z},
+			Dim: Dimensions{
+				Height: space.Dim.Height,
+				Width:  rot.Width,
+				Length: space.Dim.Length - rot.Length,
+			},
+		})
+	}
+	// 3) Slice superior (altura restante) dentro da base do item
+	if space.Dim.Height-rot.Height > 0 {
+		spaces = append(spaces, Space{
+			Origin: Position{X: origin.X, Y: origin.Y, Z: origin.Z + rot.Height},
+			Dim: Dimensions{
+				Height: space.Dim.Height - rot.Height,
+				Width:  rot.Width,
+				Length: rot.Length,
+			},
+		})
+	}
+
+	// Mantém os espaços ordenados por volume decrescente para tentar áreas maiores primeiro.
+	sort.Slice(spaces, func(i, j int) bool {
+		return spaces[i].Dim.Volume() > spaces[j].Dim.Volume()
+	})
+
+	return spaces
+}
+
+// splitMaximal recorta o item de todos os espaços que ele intercepta (não só do escolhido).
+// Cada espaço afetado gera até 6 sub-espaços maximais (um por face do item); os que ficam contidos em outro são descartados.
+func splitMaximal(spaces []Space, placed Space) []Space {
+	pe := placed.end()
+	next := make([]Space, 0, len(spaces)+6)
+
+	for _, s := range spaces {
+		if !s.intersects(placed) {
+			next = append(next, s)
+			continue
+		}
+
+		se := s.end()
+		// Sobras nos eixos X (largura), Y (comprimento) e Z (altura), antes e depois do item.
+		if placed.Origin.X > s.Origin.X {
+			next = append(next, Space{Origin: s.Origin, Dim: Dimensions{
+				Height: s.Dim.Height, Width: placed.Origin.X - s.Origin.X, Length: s.Dim.Length,
+			}})
+		}
+		if pe.X < se.X {
+			next = append(next, Space{Origin: Position{X: pe.X, Y: s.Origin.Y, Z: s.Origin.Z}, Dim: Dimensions{
+				Height: s.Dim.Height, Width: se.X - pe.X, Length: s.Dim.Length,
+			}})
+		}
+		if placed.Origin.Y > s.Origin.Y {
+			next = append(next, Space{Origin: s.Origin, Dim: Dimensions{
+				Height: s.Dim.Height, Width: s.Dim.Width, Length: placed.Origin.Y - s.Origin.Y,
+			}})
+		}
+		if pe.Y < se.Y {
+			next = append(next, Space{Origin: Position{X: s.Origin.X, Y: pe.Y, Z: s.Origin.Z}, Dim: Dimensions{
+				Height: s.Dim.Height, Width: s.Dim.Width, Length: se.Y - pe.Y,
+			}})
+		}
+		if placed.Origin.Z > s.Origin.Z {
+			next = append(next, Space{Origin: s.Origin, Dim: Dimensions{
+				Height: placed.Origin.Z - s.Origin.Z, Width: s.Dim.Width, Length: s.Dim.Length,
+			}})
+		}
+		if pe.Z < se.Z {
+			next = append(next, Space{Origin: Position{X: s.Origin.X, Y: s.Origin.Y, Z: pe.Z}, Dim: Dimensions{
+				Height: se.Z - pe.Z, Width: s.Dim.Width, Length: s.Dim.Length,
+			}})
+		}
+	}
+
+	next = removeContainedSpaces(next)
+
+	// Ordena de baixo para cima e do fundo para a frente (z, y, x) para que empates de desperdício
+	// favoreçam posições apoiadas no piso ou em itens já alocados.
+	sort.SliceStable(next, func(i, j int) bool {
+		a, b := next[i].Origin, next[j].Origin
+		if a.Z != b.Z {
+			return a.Z < b.Z
+		}
+		if a.Y != b.Y {
+			return a.Y < b.Y
+		}
+		if a.X != b.X {
+			return a.X < b.X
+		}
+		return next[i].Dim.Volume() > next[j].Dim.Volume()
+	})
+
+	return next
+}
+
+// removeContainedSpaces descarta espaços contidos em outro; em duplicatas exatas mantém a primeira ocorrência.
+func removeContainedSpaces(spaces []Space) []Space {
+	kept := make([]Space, 0, len(spaces))
+	for i, s := range spaces {
+		redundant := false
+		for j, o := range spaces {
+			if i == j || !o.contains(s) {
+				continue
+			}
+			if s == o && i < j {
+				continue
+			}
+			redundant = true
+			break
+		}
+		if !redundant {
+			kept = append(kept, s)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,57 @@
+package packing
+
+import "testing"
+
+func compareItems() []Item {
+	return []Item{
+		{ProductID: "A", Dim: Dimensions{Height: 10, Width: 15, Length: 40}, Index: 0},
+		{ProductID: "B", Dim: Dimensions{Height: 15, Width: 20, Length: 5}, Index: 1},
+		{ProductID: "C", Dim: Dimensions{Height: 15, Width: 35, Length: 40}, Index: 2},
+		{ProductID: "D", Dim: Dimensions{Height: 35, Width: 10, Length: 40}, Index: 3},
+		{ProductID: "E", Dim: Dimensions{Height: 25, Width: 25, Length: 40}, Index: 4},
+	}
+}
+
+// Espaços maximais reaproveitam o volume que a guilhotina deixa fragmentado entre fatias.
+func TestPackOrder_MaximalSpacesUsesFewerBoxesThanGuillotine(t *testing.T) {
+	guillotine, err := PackOrderWithOptions(compareItems(), AvailableBoxes(), Options{AllowRotation: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	maximal, err := PackOrderWithOptions(compareItems(), AvailableBoxes(), Options{AllowRotation: true, FreeSpace: MaximalSpaces})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(maximal.Boxes) >= len(guillotine.Boxes) {
+		t.Fatalf("expected maximal spaces to use fewer boxes than guillotine, got %d vs %d", len(maximal.Boxes), len(guillotine.Boxes))
+	}
+
+	for _, b := range maximal.Boxes {
+		for i, p := range b.Products {
+			for _, q := range b.Products[i+1:] {
+				if overlaps(p, q) {
+					t.Fatalf("products %s and %s overlap", p.ID, q.ID)
+				}
+			}
+		}
+	}
+}
+
+func TestSplitMaximal_KeepsOnlyMaximalSpaces(t *testing.T) {
+	box := Space{Dim: Dimensions{Height: 10, Width: 10, Length: 10}}
+	placed := Space{Dim: Dimensions{Height: 10, Width: 4, Length: 4}}
+
+	spaces := splitMaximal([]Space{box}, placed)
+
+	// Sobram a faixa à direita (x >= 4) e a faixa à frente (y >= 4), ambas com altura cheia e sobrepostas no canto.
+	if len(spaces) != 2 {
+		t.Fatalf("expected 2 maximal spaces, got %d: %+v", len(spaces), spaces)
+	}
+	for _, s := range spaces {
+		if s.intersects(placed) {
+			t.Fatalf("space %+v intersects placed item", s)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package packing
+
+import (
+	"math"
+	"sort"
+)
+
+// costEpsilon evita trocar de solução por diferenças de arredondamento em float.
+const costEpsilon = 1e-9
+
+// Objective atribui um custo a cada caixa de uma solução; o custo da solução é a soma. Menor é melhor.
+type Objective interface {
+	BoxCost(b PackedBox) float64
+}
+
+// BoxCountObjective minimiza o número de caixas (cada caixa custa 1), como a heurística original.
+type BoxCountObjective struct{}
+
+func (BoxCountObjective) BoxCost(PackedBox) float64 {
+	return 1
+}
+
+// CostModel estima o custo monetário de uma caixa: preço da caixa + frete pelo peso tarifável + preenchimento do vazio.
+// O peso cubado usa as dimensões externas (as que a transportadora mede); o vazio, as internas.
+// Também é um Objective, permitindo pedir a solução mais barata em vez da com menos caixas.
+type CostModel struct {
+	// DimWeightDivisor converte volume em peso cubado (ex.: 6000 cm³/kg). Zero ignora o peso cubado.
+	DimWeightDivisor float64
+	// RatePerKg é o frete por kg tarifável (maior entre peso bruto e peso cubado). Zero desliga o frete.
+	RatePerKg float64
+	// VoidFillPerVolume é o custo do material de preenchimento por unidade de volume vazio.
+	VoidFillPerVolume float64
+}
+
+func (m CostModel) BoxCost(b PackedBox) float64 {
+	cost := b.BoxType.UnitCost
+
+	if m.RatePerKg > 0 {
+		billable := b.GrossWeight()
+		if m.DimWeightDivisor > 0 {
+			billable = math.Max(billable, float64(b.BoxType.OuterDimensions().Volume())/m.DimWeightDivisor)
+		}
+		cost += billable * m.RatePerKg
+	}
+
+	cost += float64(b.boxVolume()-b.UsedVolume()) * m.VoidFillPerVolume
+	return cost
+}
+
+// Cost soma o custo de todas as caixas do resultado segundo o objetivo.
+func (r OrderPackingResult) Cost(obj Objective) float64 {
+	total := 0.0
+	for _, b := range r.Boxes {
+		total += obj.BoxCost(b)
+	}
+	return total
+}
+
+// packForObjective roda a estratégia escolhida com diferentes preferências de abertura de caixa, tenta trocar cada caixa
+// por um tipo mais barato que comporte o mesmo conteúdo e fica com a solução de menor custo.
+// Em empate prevalece a primeira variação (menor caixa primeiro), preservando o comportamento original.
+func packForObjective(items []Item, boxTypes []BoxType, opts Options) (OrderPackingResult, error) {
+	var best OrderPackingResult
+	bestCost := math.Inf(1)
+
+	for _, order := range boxPreferenceOrders(boxTypes, opts.Objective) {
+		if err := opts.canceled(); err != nil {
+			return OrderPackingResult{}, err
+		}
+		res, err := packUnits(items, order, opts)
+		if err != nil {
+			// A viabilidade de cada item não depende da ordem das caixas: o erro seria o mesmo nas demais variações.
+			return OrderPackingResult{}, err
+		}
+
+		res = downsizeBoxes(res, boxTypes, opts)
+		if cost := res.Cost(opts.Objective); cost < bestCost-costEpsilon {
+			best, bestCost = res, cost
+		}
+	}
+
+	return best, nil
+}
+
+// boxPreferenceOrders devolve as ordens de abertura avaliadas: menor volume primeiro (original),
+// menor custo por volume primeiro (consolida em caixas grandes e baratas) e menor custo da caixa vazia primeiro.
+func boxPreferenceOrders(boxTypes []BoxType, obj Objective) [][]BoxType {
+	emptyCost := make(map[string]float64, len(boxTypes))
+	for _, bt := range boxTypes {
+		emptyCost[bt.ID] = obj.BoxCost(newPackedBox(bt, Options{}))
+	}
+
+	byCostPerVolume := append([]BoxType(nil), boxTypes...)
+	sort.SliceStable(byCostPerVolume, func(i, j int) bool {
+		a, b := byCostPerVolume[i], byCostPerVolume[j]
+		return emptyCost[a.ID]/float64(a.Height*a.Width*a.Length) < emptyCost[b.ID]/float64(b.Height*b.Width*b.Length)
+	})
+
+	byCost := append([]BoxType(nil), boxTypes...)
+	sort.SliceStable(byCost, func(i, j int) bool {
+		return emptyCost[byCost[i].ID] < emptyCost[byCost[j].ID]
+	})
+
+	orders := [][]BoxType{boxTypes}
+	for _, candidate := range [][]BoxType{byCostPerVolume, byCost} {
+		duplicate := false
+		for _, o := range orders {
+			if sameBoxOrder(o, candidate) {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			orders = append(orders, candidate)
+		}
+	}
+	return orders
+}
+
+func sameBoxOrder(a, b []BoxType) bool {
+	for i := range a {
+		if a[i].ID != b[i].ID {
+			return false
+		}
+	}
+	return true
+}
+
+// downsizeBoxes tenta reempacotar o conteúdo de cada caixa, isoladamente, em cada outro tipo de caixa
+// e troca pela alternativa de menor custo quando todo o conteúdo couber.
+func downsizeBoxes(res OrderPackingResult, boxTypes []BoxType, opts Options) OrderPackingResult {
+	for i, b := range res.Boxes {
+		items := make([]Item, 0, len(b.Products))
+		for _, p := range b.Products {
+			items = append(items, p.item)
+		}
+		sortItemsByVolume(items)
+
+		bestCost := opts.Objective.BoxCost(b)
+		for _, bt := range boxTypes {
+			if bt.ID == b.BoxType.ID {
+				continue
+			}
+
+			nb, ok := packIntoSingleBox(items, bt, opts)
+			if !ok {
+				continue
+			}
+			if cost := opts.Objective.BoxCost(nb); cost < bestCost-costEpsilon {
+				res.Boxes[i], bestCost = nb, cost
+			}
+		}
+	}
+	return res
+}
+
+func packIntoSingleBox(items []Item, bt BoxType, opts Options) (PackedBox, bool) {
+	nb := newPackedBox(bt, opts)
+	for _, it := range items {
+		if !nb.TryPlace(it, opts.AllowRotation) {
+			return PackedBox{}, false
+		}
+	}
+	return nb, true
+}
@@ -0,0 +1,56 @@
+package packing
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPackOrder_CostObjectivePrefersCheaperBox(t *testing.T) {
+	boxes := []BoxType{
+		{ID: "Pequena", Height: 10, Width: 10, Length: 10, UnitCost: 10},
+		{ID: "Media", Height: 20, Width: 20, Length: 20, UnitCost: 2},
+	}
+	newItems := func() []Item {
+		return []Item{{ProductID: "A", Dim: Dimensions{Height: 10, Width: 10, Length: 10}, Index: 0}}
+	}
+
+	byCount, err := PackOrderWithOptions(newItems(), boxes, Options{AllowRotation: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if byCount.Boxes[0].BoxType.ID != "Pequena" {
+		t.Fatalf("expected smallest box without objective, got %s", byCount.Boxes[0].BoxType.ID)
+	}
+
+	model := CostModel{}
+	byCost, err := PackOrderWithOptions(newItems(), boxes, Options{AllowRotation: true, Objective: model})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if byCost.Boxes[0].BoxType.ID != "Media" {
+		t.Fatalf("expected cheaper Media box, got %s", byCost.Boxes[0].BoxType.ID)
+	}
+	if got := byCost.Cost(model); got != 2 {
+		t.Fatalf("expected total cost 2, got %v", got)
+	}
+}
+
+func TestCostModel_ChargesDimensionalWeightAndVoidFill(t *testing.T) {
+	b := newPackedBox(BoxType{ID: "X", Height: 10, Width: 10, Length: 60, UnitCost: 1, TareWeight: 0.2}, Options{})
+	if !b.TryPlace(Item{ProductID: "A", Dim: Dimensions{Height: 10, Width: 10, Length: 10}, Weight: 0.3}, true) {
+		t.Fatalf("expected item to fit")
+	}
+
+	model := CostModel{DimWeightDivisor: 1000, RatePerKg: 2, VoidFillPerVolume: 0.001}
+
+	// Peso cubado 6000/1000 = 6 kg supera o bruto (0,5 kg): frete 12; vazio 5000 * 0,001 = 5; caixa 1.
+	if got := model.BoxCost(b); got != 18 {
+		t.Fatalf("expected cost 18, got %v", got)
+	}
+
+	// Com parede de 1, a caixa fechada mede 12x12x62 = 8928: peso cubado 8,928 kg, frete 17,856; o vazio interno não muda.
+	b.BoxType.WallThickness = 1
+	if got := model.BoxCost(b); math.Abs(got-23.856) > 1e-9 {
+		t.Fatalf("expected cost 23.856 using outer dimensions, got %v", got)
+	}
+}
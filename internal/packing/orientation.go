@@ -0,0 +1,66 @@
+package packing
+
+// Orientation define quais rotações um item aceita.
+type Orientation string
+
+const (
+	// OrientationDefault segue o Options.AllowRotation do empacotamento.
+	OrientationDefault Orientation = ""
+	// OrientationFree permite as 6 permutações 3D.
+	OrientationFree Orientation = "free"
+	// OrientationUpright mantém a altura na vertical ("este lado para cima"); só gira em torno do eixo vertical.
+	OrientationUpright Orientation = "upright"
+	// OrientationFixed mantém exatamente a orientação informada.
+	OrientationFixed Orientation = "fixed"
+)
+
+// rotationsFor devolve as orientações candidatas para a regra informada, sempre começando pela original.
+func rotationsFor(d Dimensions, o Orientation) []Dimensions {
+	switch o {
+	case OrientationFree:
+		return d.Rotations()
+	case OrientationUpright:
+		if d.Width == d.Length {
+			return []Dimensions{d}
+		}
+		return []Dimensions{d, {Height: d.Height, Width: d.Length, Length: d.Width}}
+	default:
+		return []Dimensions{d}
+	}
+}
+
+// effectiveOrientation resolve a regra do item; sem regra própria, allowRotation decide entre livre e fixa.
+func (it Item) effectiveOrientation(allowRotation bool) Orientation {
+	if it.Orientation != OrientationDefault {
+		return it.Orientation
+	}
+	if allowRotation {
+		return OrientationFree
+	}
+	return OrientationFixed
+}
+
+func (it Item) rotations(allowRotation bool) []Dimensions {
+	return rotationsFor(it.Dim, it.effectiveOrientation(allowRotation))
+}
+
+// orientationLocked informa se o item só não cabe por causa da sua regra de orientação
+// (caberia em alguma caixa se pudesse girar livremente). Peso não é considerado aqui.
+func (it Item) orientationLocked(boxTypes []BoxType) bool {
+	if it.Orientation != OrientationUpright && it.Orientation != OrientationFixed {
+		return false
+	}
+
+	allowed := rotationsFor(it.Dim, it.Orientation)
+	free := it.Dim.Rotations()
+	for _, bt := range boxTypes {
+		space := Dimensions{Height: bt.Height, Width: bt.Width, Length: bt.Length}
+		if fitsAnyRotation(allowed, space) {
+			return false
+		}
+		if fitsAnyRotation(free, space) {
+			return true
+		}
+	}
+	return false
+}
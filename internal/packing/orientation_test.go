@@ -0,0 +1,66 @@
+package packing
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRotationsFor_UprightKeepsHeightVertical(t *testing.T) {
+	rots := rotationsFor(Dimensions{Height: 10, Width: 20, Length: 30}, OrientationUpright)
+
+	if len(rots) != 2 {
+		t.Fatalf("expected 2 upright rotations, got %d", len(rots))
+	}
+	for _, r := range rots {
+		if r.Height != 10 {
+			t.Fatalf("upright rotation changed height: %+v", r)
+		}
+	}
+}
+
+func TestPackOrder_PerItemOrientationOverridesGlobalRotation(t *testing.T) {
+	dim := Dimensions{Height: 60, Width: 30, Length: 40}
+
+	free, err := PackOrder([]Item{{ProductID: "Galão", Dim: dim, Index: 0}}, AvailableBoxes(), true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Deitado (30x40x60), cabe na Caixa 1.
+	if free.Boxes[0].BoxType.ID != "Caixa 1" {
+		t.Fatalf("expected Caixa 1 with free rotation, got %s", free.Boxes[0].BoxType.ID)
+	}
+
+	// Em pé, altura 60 não cabe em nenhuma caixa (máximo 50).
+	_, err = PackOrder([]Item{{ProductID: "Galão", Dim: dim, Orientation: OrientationUpright, Index: 0}}, AvailableBoxes(), true)
+	if err == nil || !strings.Contains(err.Error(), "orientação") {
+		t.Fatalf("expected orientation error, got %v", err)
+	}
+}
+
+func TestPackOrder_UprightItemMayTurnAroundVerticalAxis(t *testing.T) {
+	items := []Item{
+		{ProductID: "TV", Dim: Dimensions{Height: 45, Width: 50, Length: 75}, Orientation: OrientationUpright, Index: 0},
+	}
+
+	res, err := PackOrder(items, AvailableBoxes(), false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Girando em torno do eixo vertical (largura 75, comprimento 50), cabe na Caixa 3 mesmo com rotação global desligada.
+	rot := res.Boxes[0].Products[0].Rotation
+	if res.Boxes[0].BoxType.ID != "Caixa 3" || rot.Height != 45 || rot.Width != 75 {
+		t.Fatalf("expected upright TV turned inside Caixa 3, got %s %+v", res.Boxes[0].BoxType.ID, rot)
+	}
+}
+
+func TestPackOrder_OrientationLockedItemReportsOrientation(t *testing.T) {
+	items := []Item{
+		{ProductID: "Garrafa", Dim: Dimensions{Height: 80, Width: 30, Length: 40}, Orientation: OrientationFixed, Index: 0},
+	}
+
+	_, err := PackOrder(items, AvailableBoxes(), true)
+	if err == nil || !strings.Contains(err.Error(), "orientação") {
+		t.Fatalf("expected orientation error, got %v", err)
+	}
+}
@@ -0,0 +1,263 @@
+package packing
+
+// Packer é uma estratégia de empacotamento de um pedido.
+// Recebe os itens já ordenados por volume decrescente e as caixas na ordem de preferência de abertura;
+// não deve alterar os slices recebidos, pois BestOf e o objetivo de custo reutilizam a mesma entrada.
+type Packer interface {
+	Pack(items []Item, boxTypes []BoxType, opts Options) (OrderPackingResult, error)
+}
+
+func (o Options) packer() Packer {
+	if o.Packer == nil {
+		return FirstFitDecreasing{}
+	}
+	return o.Packer
+}
+
+// FirstFitDecreasing coloca cada item na primeira caixa aberta em que ele cabe (heurística original).
+// As unidades de uma linha com quantidade são alocadas em blocos (ver placeRun), o que é mais rápido mas pode abrir
+// mais caixas do que tentá-las uma a uma; packUnits compara as duas formas em pedidos pequenos.
+type FirstFitDecreasing struct{}
+
+func (FirstFitDecreasing) Pack(items []Item, boxTypes []BoxType, opts Options) (OrderPackingResult, error) {
+	var opened []PackedBox
+
+	for i := 0; i < len(items); {
+		if err := opts.canceled(); err != nil {
+			return OrderPackingResult{}, err
+		}
+		end := opts.runEnd(items, i)
+		run := items[i:end]
+		i = end
+
+		// Try existing boxes first
+		for bi := range opened {
+			if len(run) == 0 {
+				break
+			}
+			run = run[opened[bi].placeRun(run, opts.AllowRotation):]
+		}
+
+		for len(run) > 0 {
+			nb, err := openBox(run[0], boxTypes, opts)
+			if err != nil {
+				return OrderPackingResult{}, err
+			}
+			run = run[1:]
+			run = run[nb.placeRun(run, opts.AllowRotation):]
+			opened = append(opened, nb)
+		}
+	}
+
+	return OrderPackingResult{Boxes: opened}, nil
+}
+
+// BestFitDecreasing coloca cada item na caixa aberta que fica com menos volume livre após recebê-lo,
+// concentrando os itens pequenos nas caixas mais cheias. As unidades de uma mesma linha seguem em blocos para a caixa escolhida.
+type BestFitDecreasing struct{}
+
+func (BestFitDecreasing) Pack(items []Item, boxTypes []BoxType, opts Options) (OrderPackingResult, error) {
+	var opened []PackedBox
+
+	for i := 0; i < len(items); {
+		end := opts.runEnd(items, i)
+		run := items[i:end]
+		i = end
+
+		for len(run) > 0 {
+			if err := opts.canceled(); err != nil {
+				return OrderPackingResult{}, err
+			}
+			it := run[0]
+			bestBox := -1
+			bestResidual := 0
+
+			for bi := range opened {
+				if _, ok := opened[bi].findPlacement(it, opts.AllowRotation); !ok {
+					continue
+				}
+				residual := opened[bi].boxVolume() - opened[bi].UsedVolume() - it.Dim.Volume()
+				if bestBox == -1 || residual < bestResidual {
+					bestBox, bestResidual = bi, residual
+				}
+			}
+
+			if bestBox != -1 {
+				run = run[opened[bestBox].placeBlock(run, opts.AllowRotation):]
+				continue
+			}
+
+			nb, err := openBox(it, boxTypes, opts)
+			if err != nil {
+				return OrderPackingResult{}, err
+			}
+			opened = append(opened, nb)
+			run = run[1:]
+		}
+	}
+
+	return OrderPackingResult{Boxes: opened}, nil
+}
+
+// LayerPacker monta uma caixa por vez em camadas horizontais (wall-building): a altura de cada camada é dada
+// pelo primeiro item pendente que ainda cabe, deitado sobre sua maior face, e a camada é preenchida com os itens
+// seguintes que couberem nela. Entre os tipos de caixa, prefere o menor que comporte todo o restante;
+// se nenhum comportar, o que acomodar mais volume.
+type LayerPacker struct{}
+
+func (LayerPacker) Pack(items []Item, boxTypes []BoxType, opts Options) (OrderPackingResult, error) {
+	remaining := append([]Item(nil), items...)
+	var opened []PackedBox
+
+	for len(remaining) > 0 {
+		if err := opts.canceled(); err != nil {
+			return OrderPackingResult{}, err
+		}
+		// Garante que o próximo item cabe em alguma caixa e reaproveita a mensagem de erro das demais estratégias.
+		if _, err := openBox(remaining[0], boxTypes, opts); err != nil {
+			return OrderPackingResult{}, err
+		}
+
+		var best PackedBox
+		var bestRest []Item
+		found := false
+
+		for _, bt := range boxTypes {
+			b, rest := fillLayers(bt, remaining, opts)
+			if len(b.Products) == 0 {
+				continue
+			}
+
+			switch {
+			case !found:
+			case len(bestRest) == 0:
+				// Já há uma caixa (menor, pela ordem de preferência) que leva todo o restante.
+				continue
+			case len(rest) > 0 && b.UsedVolume() <= best.UsedVolume():
+				continue
+			}
+			best, bestRest, found = b, rest, true
+		}
+
+		opened = append(opened, best)
+		remaining = bestRest
+	}
+
+	return OrderPackingResult{Boxes: opened}, nil
+}
+
+// fillLayers empacota os itens em camadas dentro de uma caixa do tipo informado e devolve os que sobraram, na mesma ordem.
+func fillLayers(bt BoxType, items []Item, opts Options) (PackedBox, []Item) {
+	b := newPackedBox(bt, opts)
+	pending := append([]Item(nil), items...)
+	var leftovers []Space
+	z := 0
+
+	for z < bt.Height && len(pending) > 0 {
+		h := layerHeight(&b, pending, bt.Height-z, opts)
+		if h == 0 {
+			break
+		}
+
+		b.freeSpaces = []Space{{
+			Origin: Position{Z: z},
+			Dim:    Dimensions{Height: h, Width: bt.Width, Length: bt.Length},
+		}}
+
+		rest := make([]Item, 0, len(pending))
+		for i := 0; i < len(pending); {
+			end := opts.runEnd(pending, i)
+			run := pending[i:end]
+			rest = append(rest, run[b.placeRun(run, opts.AllowRotation):]...)
+			i = end
+		}
+
+		leftovers = append(leftovers, b.freeSpaces...)
+		pending = rest
+		z += h
+	}
+
+	// Sobras das camadas mais o espaço acima da última continuam registrados como espaço livre da caixa.
+	if z < bt.Height {
+		leftovers = append(leftovers, Space{
+			Origin: Position{Z: z},
+			Dim:    Dimensions{Height: bt.Height - z, Width: bt.Width, Length: bt.Length},
+		})
+	}
+	b.freeSpaces = leftovers
+
+	return b, pending
+}
+
+// layerHeight devolve a altura da próxima camada: a do primeiro item pendente que cabe no espaço restante,
+// na rotação de maior base (em empate, a mais baixa). Zero quando nenhum item cabe.
+func layerHeight(b *PackedBox, pending []Item, maxHeight int, opts Options) int {
+	space := Dimensions{Height: maxHeight, Width: b.BoxType.Width, Length: b.BoxType.Length}
+
+	for _, it := range pending {
+		if !b.BoxType.CanCarry(b.ContentWeight + it.Weight) {
+			continue
+		}
+
+		height, area := 0, 0
+		for _, rot := range it.rotations(opts.AllowRotation) {
+			if !rot.FitsIn(space) {
+				continue
+			}
+			a := rot.Width * rot.Length
+			if a > area || (a == area && rot.Height < height) {
+				height, area = rot.Height, a
+			}
+		}
+		if height > 0 {
+			return height
+		}
+	}
+	return 0
+}
+
+// BestOf roda várias estratégias e fica com o melhor resultado segundo o objetivo (número de caixas por padrão).
+// Sem Packers, avalia FirstFitDecreasing, BestFitDecreasing e LayerPacker. Em empate prevalece a estratégia listada primeiro.
+type BestOf struct {
+	Packers []Packer
+}
+
+func (p BestOf) Pack(items []Item, boxTypes []BoxType, opts Options) (OrderPackingResult, error) {
+	packers := p.Packers
+	if len(packers) == 0 {
+		packers = []Packer{FirstFitDecreasing{}, BestFitDecreasing{}, LayerPacker{}}
+	}
+
+	var obj Objective = BoxCountObjective{}
+	if opts.Objective != nil {
+		obj = opts.Objective
+	}
+
+	var best OrderPackingResult
+	var firstErr error
+	found := false
+	bestCost := 0.0
+
+	for _, packer := range packers {
+		if err := opts.canceled(); err != nil {
+			return OrderPackingResult{}, err
+		}
+		res, err := packer.Pack(items, boxTypes, opts)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+
+		cost := res.Cost(obj)
+		if !found || cost < bestCost-costEpsilon {
+			best, bestCost, found = res, cost, true
+		}
+	}
+
+	if !found {
+		return OrderPackingResult{}, firstErr
+	}
+	return best, nil
+}
@@ -0,0 +1,119 @@
+package packing
+
+import "testing"
+
+func TestPackers_ProduceValidPackings(t *testing.T) {
+	packers := map[string]Packer{
+		"ffd":    FirstFitDecreasing{},
+		"bfd":    BestFitDecreasing{},
+		"layers": LayerPacker{},
+		"best":   BestOf{},
+	}
+
+	for name, packer := range packers {
+		res, err := PackOrderWithOptions(compareItems(), AvailableBoxes(), Options{AllowRotation: true, Packer: packer})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+
+		packed := 0
+		for _, b := range res.Boxes {
+			for i, p := range b.Products {
+				packed++
+				if p.Position.X+p.Rotation.Width > b.BoxType.Width ||
+					p.Position.Y+p.Rotation.Length > b.BoxType.Length ||
+					p.Position.Z+p.Rotation.Height > b.BoxType.Height {
+					t.Fatalf("%s: product %s exceeds box %s", name, p.ID, b.BoxType.ID)
+				}
+				for _, q := range b.Products[i+1:] {
+					if overlaps(p, q) {
+						t.Fatalf("%s: products %s and %s overlap", name, p.ID, q.ID)
+					}
+				}
+			}
+		}
+		if packed != len(compareItems()) {
+			t.Fatalf("%s: expected %d products packed, got %d", name, len(compareItems()), packed)
+		}
+	}
+}
+
+func TestBestFitDecreasing_PrefersFullestBox(t *testing.T) {
+	boxes := []BoxType{{ID: "Cubo", Height: 10, Width: 10, Length: 10, MaxWeight: 10}}
+	items := func() []Item {
+		return []Item{
+			{ProductID: "A", Dim: Dimensions{Height: 5, Width: 10, Length: 10}, Weight: 9, Index: 0},
+			{ProductID: "B", Dim: Dimensions{Height: 4, Width: 10, Length: 10}, Weight: 2, Index: 1},
+			{ProductID: "C", Dim: Dimensions{Height: 3, Width: 10, Length: 10}, Weight: 5, Index: 2},
+			{ProductID: "D", Dim: Dimensions{Height: 1, Width: 10, Length: 10}, Weight: 0.5, Index: 3},
+		}
+	}
+
+	boxOf := func(res OrderPackingResult, id string) int {
+		for bi, b := range res.Boxes {
+			for _, p := range b.Products {
+				if p.ID == id {
+					return bi
+				}
+			}
+		}
+		return -1
+	}
+
+	ffd, err := PackOrderWithOptions(items(), boxes, Options{Packer: FirstFitDecreasing{}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	bfd, err := PackOrderWithOptions(items(), boxes, Options{Packer: BestFitDecreasing{}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := boxOf(ffd, "D"); got != 0 {
+		t.Fatalf("expected first-fit to put D in the first box, got box %d", got)
+	}
+	if got := boxOf(bfd, "D"); got != 1 {
+		t.Fatalf("expected best-fit to put D in the fuller second box, got box %d", got)
+	}
+}
+
+func TestLayerPacker_StacksLayers(t *testing.T) {
+	boxes := []BoxType{{ID: "Cubo", Height: 10, Width: 10, Length: 10}}
+	items := []Item{
+		{ProductID: "A", Dim: Dimensions{Height: 5, Width: 10, Length: 5}, Index: 0},
+		{ProductID: "B", Dim: Dimensions{Height: 5, Width: 10, Length: 5}, Index: 1},
+		{ProductID: "C", Dim: Dimensions{Height: 5, Width: 10, Length: 10}, Index: 2},
+	}
+
+	res, err := PackOrderWithOptions(items, boxes, Options{Packer: LayerPacker{}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Boxes) != 1 {
+		t.Fatalf("expected 1 box, got %d", len(res.Boxes))
+	}
+	for _, p := range res.Boxes[0].Products {
+		if p.Position.Z != 0 && p.Position.Z != 5 {
+			t.Fatalf("expected products on layers z=0 or z=5, got %s at z=%d", p.ID, p.Position.Z)
+		}
+	}
+}
+
+func TestBestOf_NoWorseThanEachStrategy(t *testing.T) {
+	strategies := []Packer{FirstFitDecreasing{}, BestFitDecreasing{}, LayerPacker{}}
+
+	best, err := PackOrderWithOptions(compareItems(), AvailableBoxes(), Options{AllowRotation: true, Packer: BestOf{}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, s := range strategies {
+		res, err := PackOrderWithOptions(compareItems(), AvailableBoxes(), Options{AllowRotation: true, Packer: s})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(best.Boxes) > len(res.Boxes) {
+			t.Fatalf("best-of used %d boxes, but %T used %d", len(best.Boxes), s, len(res.Boxes))
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package packing
+
+// Empilhamento: cada produto alocado registra quais produtos o apoiam (face superior encostada na sua face inferior,
+// com sobreposição de base) e quanto peso carrega. O modelo é conservador: o peso de um item é somado por inteiro
+// a todos os itens abaixo dele na cadeia de apoio, sem dividir entre apoios.
+// A cadeia só é mantida quando algum item tem limite de carga; até lá, alocar não paga pela varredura de contatos.
+
+// loadLimited informa se o item restringe o que pode ser apoiado sobre ele.
+func (it Item) loadLimited() bool {
+	return it.Fragile || it.MaxLoadOnTop > 0
+}
+
+// canBear informa se o item suporta a carga informada sobre ele.
+func (it Item) canBear(load float64) bool {
+	if it.Fragile {
+		return false
+	}
+	return it.MaxLoadOnTop <= 0 || load <= it.MaxLoadOnTop+costEpsilon
+}
+
+// overlapsBase informa se as projeções no piso (x, y) de dois volumes se sobrepõem com área positiva.
+func overlapsBase(a, b Space) bool {
+	ae, be := a.end(), b.end()
+	return a.Origin.X < be.X && b.Origin.X < ae.X && a.Origin.Y < be.Y && b.Origin.Y < ae.Y
+}
+
+func (p packedProduct) space() Space {
+	return Space{Origin: p.Position, Dim: p.Rotation}
+}
+
+// contacts devolve os produtos logo abaixo (que apoiariam o volume) e logo acima (que passariam a se apoiar nele).
+func (b *PackedBox) contacts(placed Space) (below, above []int) {
+	top := placed.Origin.Z + placed.Dim.Height
+	for i, p := range b.Products {
+		ps := p.space()
+		if !overlapsBase(ps, placed) {
+			continue
+		}
+		switch {
+		case ps.end().Z == placed.Origin.Z:
+			below = append(below, i)
+		case ps.Origin.Z == top:
+			above = append(above, i)
+		}
+	}
+	return below, above
+}
+
+// carriedBy soma o peso (com a carga) dos produtos que ficariam apoiados sobre o volume.
+func (b *PackedBox) carriedBy(above []int) float64 {
+	load := 0.0
+	for _, i := range above {
+		load += b.Products[i].item.Weight + b.Products[i].load
+	}
+	return load
+}
+
+// ancestors percorre a cadeia de apoio a partir dos produtos informados, visitando cada produto uma vez.
+func (b *PackedBox) ancestors(start []int, visit func(i int) bool) bool {
+	seen := make(map[int]bool, len(start))
+	stack := append([]int(nil), start...)
+	for len(stack) > 0 {
+		i := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if seen[i] {
+			continue
+		}
+		seen[i] = true
+		if !visit(i) {
+			return false
+		}
+		stack = append(stack, b.Products[i].supporters...)
+	}
+	return true
+}
+
+// canStack verifica se alocar o item no volume respeita a carga máxima dele e de todos os itens que o apoiariam.
+func (b *PackedBox) canStack(item Item, placed Space) bool {
+	below, above := b.contacts(placed)
+	if len(below) == 0 && len(above) == 0 {
+		return true
+	}
+
+	carried := b.carriedBy(above)
+	if len(above) > 0 && !item.canBear(carried) {
+		return false
+	}
+
+	added := item.Weight + carried
+	return b.ancestors(below, func(i int) bool {
+		p := b.Products[i]
+		return p.item.canBear(p.load + added)
+	})
+}
+
+// trackSupport monta, na primeira vez em que há limite de carga em jogo, a cadeia de apoio dos produtos já alocados,
+// religando-os na ordem em que foram colocados. Daí em diante, record mantém a cadeia a cada alocação.
+func (b *PackedBox) trackSupport() {
+	if b.supportKnown {
+		return
+	}
+	b.supportKnown = true
+
+	placed := b.Products
+	b.Products = make([]packedProduct, 0, cap(placed))
+	for _, p := range placed {
+		p.supporters, p.load = nil, 0
+		b.link(&p)
+		b.Products = append(b.Products, p)
+	}
+}
+
+// link liga o produto (ainda fora de Products) à cadeia de apoio: passa a carregar o que já estiver logo acima
+// e soma seu peso (com essa carga) a todos os produtos abaixo dele.
+func (b *PackedBox) link(p *packedProduct) {
+	below, above := b.contacts(p.space())
+	p.supporters = below
+	p.load = b.carriedBy(above)
+
+	added := p.item.Weight + p.load
+	b.ancestors(below, func(i int) bool {
+		b.Products[i].load += added
+		return true
+	})
+	for _, i := range above {
+		b.Products[i].supporters = append(b.Products[i].supporters, len(b.Products))
+	}
+}
+
+// stackingChecked informa se alocar o item exige verificar cargas: só quando ele ou algum produto já na caixa tem limite.
+func (b *PackedBox) stackingChecked(item Item) bool {
+	return b.loadLimited || item.loadLimited()
+}
@@ -0,0 +1,62 @@
+package packing
+
+import "testing"
+
+func stackItem(id string, weight float64) Item {
+	return Item{ProductID: id, Dim: Dimensions{Height: 10, Width: 10, Length: 10}, Weight: weight}
+}
+
+func TestPackOrder_NothingOnTopOfFragileItem(t *testing.T) {
+	boxes := []BoxType{{ID: "Torre", Height: 20, Width: 10, Length: 10}}
+
+	newItems := func(fragile bool) []Item {
+		a := stackItem("A", 1)
+		a.Fragile = fragile
+		return []Item{a, stackItem("B", 15)}
+	}
+
+	res, err := PackOrderWithOptions(newItems(false), boxes, Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Boxes) != 1 {
+		t.Fatalf("expected items stacked in 1 box, got %d", len(res.Boxes))
+	}
+
+	res, err = PackOrderWithOptions(newItems(true), boxes, Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Boxes) != 2 {
+		t.Fatalf("expected fragile item alone, got %d boxes", len(res.Boxes))
+	}
+}
+
+func TestPackOrder_MaxLoadOnTopCountsWholeStack(t *testing.T) {
+	boxes := []BoxType{{ID: "Torre", Height: 30, Width: 10, Length: 10}}
+	a := stackItem("A", 1)
+	a.MaxLoadOnTop = 10
+	items := []Item{a, stackItem("B", 5), stackItem("C", 6)}
+
+	res, err := PackOrderWithOptions(items, boxes, Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Boxes) != 2 {
+		t.Fatalf("expected C moved to a second box (A would carry 11 kg), got %d boxes", len(res.Boxes))
+	}
+
+	first := res.Boxes[0]
+	if len(first.Products) != 2 || first.Products[1].ID != "B" {
+		t.Fatalf("expected A and B stacked in the first box, got %+v", first.Products)
+	}
+	if got := first.Products[1].supporters; len(got) != 1 || got[0] != 0 {
+		t.Fatalf("expected B supported by A, got %v", got)
+	}
+	if got := first.Products[0].load; got != 5 {
+		t.Fatalf("expected A carrying 5 kg, got %v", got)
+	}
+	if res.Boxes[1].Products[0].ID != "C" {
+		t.Fatalf("expected C in the second box, got %s", res.Boxes[1].Products[0].ID)
+	}
+}
@@ -0,0 +1,31 @@
+package packing
+
+// Estabilidade: com Options.MinSupportRatio, um item só é aceito se uma fração mínima da sua base estiver
+// apoiada no piso da caixa ou no topo de itens já alocados. Impede itens "flutuando" sobre fatias de espaço livre
+// que a divisão em guilhotina (ou os espaços maximais) criam acima de apoios parciais.
+
+// supportedArea soma a área da base do volume que se apoia no topo dos produtos logo abaixo.
+// Como os produtos não se sobrepõem, as áreas de contato não se repetem.
+func (b *PackedBox) supportedArea(placed Space) int {
+	area := 0
+	for _, p := range b.Products {
+		ps := p.space()
+		if ps.end().Z != placed.Origin.Z || !overlapsBase(ps, placed) {
+			continue
+		}
+		pe, e := ps.end(), placed.end()
+		w := min(pe.X, e.X) - max(ps.Origin.X, placed.Origin.X)
+		l := min(pe.Y, e.Y) - max(ps.Origin.Y, placed.Origin.Y)
+		area += w * l
+	}
+	return area
+}
+
+// supported informa se o volume respeita o apoio mínimo da caixa; no piso, o apoio é sempre total.
+func (b *PackedBox) supported(placed Space) bool {
+	if b.minSupport <= 0 || placed.Origin.Z == 0 {
+		return true
+	}
+	base := placed.Dim.Width * placed.Dim.Length
+	return float64(b.supportedArea(placed)) >= b.minSupport*float64(base)-costEpsilon
+}
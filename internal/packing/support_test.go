@@ -0,0 +1,55 @@
+package packing
+
+import "testing"
+
+// A ocupa 60% do piso; acima dele sobra um espaço maximal que cobre a caixa inteira, onde B ficaria 40% sem apoio.
+func supportItems() []Item {
+	return []Item{
+		{ProductID: "A", Dim: Dimensions{Height: 8, Width: 10, Length: 6}, Index: 0},
+		{ProductID: "B", Dim: Dimensions{Height: 2, Width: 10, Length: 10}, Index: 1},
+	}
+}
+
+func TestPackOrder_MinSupportRatio(t *testing.T) {
+	boxes := []BoxType{{ID: "Cubo", Height: 10, Width: 10, Length: 10}}
+
+	cases := []struct {
+		ratio     float64
+		wantBoxes int
+	}{
+		{ratio: 0, wantBoxes: 1},
+		{ratio: 0.5, wantBoxes: 1},
+		{ratio: 0.75, wantBoxes: 2},
+	}
+
+	for _, tc := range cases {
+		res, err := PackOrderWithOptions(supportItems(), boxes, Options{FreeSpace: MaximalSpaces, MinSupportRatio: tc.ratio})
+		if err != nil {
+			t.Fatalf("ratio %v: unexpected error: %v", tc.ratio, err)
+		}
+		if len(res.Boxes) != tc.wantBoxes {
+			t.Fatalf("ratio %v: expected %d boxes, got %d", tc.ratio, tc.wantBoxes, len(res.Boxes))
+		}
+	}
+}
+
+func TestPackers_RespectMinSupportRatio(t *testing.T) {
+	packers := []Packer{FirstFitDecreasing{}, BestFitDecreasing{}, LayerPacker{}}
+
+	for _, fs := range []FreeSpaceStrategy{GuillotineSplit, MaximalSpaces} {
+		for _, packer := range packers {
+			opts := Options{AllowRotation: true, FreeSpace: fs, Packer: packer, MinSupportRatio: 1}
+			res, err := PackOrderWithOptions(compareItems(), AvailableBoxes(), opts)
+			if err != nil {
+				t.Fatalf("%T/%v: unexpected error: %v", packer, fs, err)
+			}
+			for _, b := range res.Boxes {
+				for _, p := range b.Products {
+					if p.Position.Z > 0 && b.supportedArea(p.space()) < p.Rotation.Width*p.Rotation.Length {
+						t.Fatalf("%T/%v: product %s is not fully supported at z=%d", packer, fs, p.ID, p.Position.Z)
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package packing
+
+import "sort"
+
+// UnpackedReason explica por que um item ficou fora das caixas.
+type UnpackedReason string
+
+const (
+	// ReasonTooLarge: o item não cabe em nenhuma caixa em nenhuma das orientações testadas.
+	ReasonTooLarge UnpackedReason = "too_large"
+	// ReasonOverWeight: o item cabe em alguma caixa, mas excede a carga máxima de todas elas.
+	ReasonOverWeight UnpackedReason = "over_weight"
+	// ReasonOrientationLocked: o item só caberia se fosse girado além do que sua orientação permite.
+	ReasonOrientationLocked UnpackedReason = "orientation_locked"
+)
+
+// UnpackableError indica que um item não cabe em nenhuma das caixas disponíveis, com o motivo.
+type UnpackableError struct {
+	ProductID string
+	Reason    UnpackedReason
+	msg       string
+}
+
+func (e *UnpackableError) Error() string {
+	return e.msg
+}
+
+// UnpackedItem é um item deixado de fora do empacotamento parcial.
+type UnpackedItem struct {
+	Item    Item
+	Reason  UnpackedReason
+	Message string
+}
+
+// splitUnpackable separa os itens que não cabem sozinhos em nenhuma caixa; a viabilidade de cada item
+// independe dos demais, então o restante pode ser empacotado normalmente. Mantém a ordem dos itens empacotáveis.
+func splitUnpackable(items []Item, boxTypes []BoxType, opts Options) ([]Item, []UnpackedItem) {
+	packable := make([]Item, 0, len(items))
+	var unpacked []UnpackedItem
+
+	for _, it := range items {
+		_, err := chooseBoxType(it, boxTypes, opts)
+		if err == nil {
+			packable = append(packable, it)
+			continue
+		}
+		ue := err.(*UnpackableError)
+		unpacked = append(unpacked, UnpackedItem{Item: it, Reason: ue.Reason, Message: ue.Error()})
+	}
+
+	sort.Slice(unpacked, func(i, j int) bool {
+		return unpacked[i].Item.Index < unpacked[j].Item.Index
+	})
+	return packable, unpacked
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/warley004/packing-optimizer-api/internal/api/dto"
+	"github.com/warley004/packing-optimizer-api/internal/catalog"
+	"github.com/warley004/packing-optimizer-api/internal/packing"
+)
+
+// resolveBoxes aplica a sobrescrita de caixas de um pedido (ou do lote) sobre o catálogo ativo.
+// Erros de estrutura (combinação inválida, IDs duplicados) viram 400; IDs que não existem no catálogo viram 422.
+func resolveBoxes(catalogBoxes []packing.BoxType, permitidas []string, inline []dto.CaixaRequest) ([]packing.BoxType, error) {
+	if len(permitidas) > 0 && len(inline) > 0 {
+		return nil, &ServiceError{
+			StatusCode: http.StatusBadRequest,
+			Code:       "VALIDATION_ERROR",
+			Message:    "informe caixas_permitidas ou caixas, não ambos",
+		}
+	}
+
+	if len(inline) > 0 {
+		boxes, err := catalog.BoxesFromDTO(inline)
+		if err != nil {
+			return nil, &ServiceError{
+				StatusCode: http.StatusBadRequest,
+				Code:       "VALIDATION_ERROR",
+				Message:    "caixas inválidas: " + err.Error(),
+			}
+		}
+		return boxes, nil
+	}
+
+	if len(permitidas) == 0 {
+		return catalogBoxes, nil
+	}
+
+	byID := make(map[string]packing.BoxType, len(catalogBoxes))
+	for _, bt := range catalogBoxes {
+		byID[bt.ID] = bt
+	}
+
+	boxes := make([]packing.BoxType, 0, len(permitidas))
+	seen := make(map[string]bool, len(permitidas))
+	var unknown []string
+	for _, id := range permitidas {
+		if seen[id] {
+			return nil, &ServiceError{
+				StatusCode: http.StatusBadRequest,
+				Code:       "VALIDATION_ERROR",
+				Message:    "caixas_permitidas com caixa_id '" + id + "' duplicado",
+			}
+		}
+		seen[id] = true
+
+		bt, ok := byID[id]
+		if !ok {
+			unknown = append(unknown, id)
+			continue
+		}
+		boxes = append(boxes, bt)
+	}
+
+	// IDs inexistentes (ou aposentados) dependem do estado do catálogo, não da estrutura do JSON: 422.
+	if len(unknown) > 0 {
+		return nil, &ServiceError{
+			StatusCode: http.StatusUnprocessableEntity,
+			Code:       "UNKNOWN_BOX",
+			Message:    "caixas_permitidas referencia caixas inexistentes ou aposentadas: " + strings.Join(unknown, ", "),
+		}
+	}
+
+	return boxes, nil
+}
+
+// withOrderPrefix contextualiza o erro com o pedido, mantendo status e código originais.
+func withOrderPrefix(pedidoID int64, err error) error {
+	var se *ServiceError
+	if errors.As(err, &se) {
+		return &ServiceError{
+			StatusCode: se.StatusCode,
+			Code:       se.Code,
+			Message:    "Pedido " + formatID(pedidoID) + ": " + se.Message,
+		}
+	}
+	return err
+}
@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/warley004/packing-optimizer-api/internal/api/dto"
+)
+
+// JobSettings dimensiona a fila de jobs assíncronos.
+type JobSettings struct {
+	// QueueSize é o máximo de jobs aguardando processamento; acima disso novos jobs são recusados.
+	QueueSize int
+	// ResultTTL é por quanto tempo um job finalizado (e seu resultado) continua consultável.
+	ResultTTL time.Duration
+}
+
+// JobManager processa lotes grandes fora do ciclo do request: os jobs entram em uma fila limitada e são executados
+// um de cada vez pelo mesmo pool de workers de PackingService.Pack, então um lote assíncrono ocupa os mesmos
+// núcleos que um lote síncrono e reveza os workers com eles. O estado fica em memória e se perde ao reiniciar a API.
+type JobManager struct {
+	service  *PackingService
+	settings JobSettings
+	now      func() time.Time
+
+	mu    sync.Mutex
+	ready *sync.Cond
+	// queue são os jobs aguardando o executor, na ordem de chegada; Cancel tira da fila os que ainda não começaram,
+	// então só jobs pendentes de verdade contam para QueueSize.
+	queue []*packingJob
+	jobs  map[string]*packingJob
+}
+
+type packingJob struct {
+	id     string
+	req    dto.PackingRequest
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	status     string
+	done       int
+	result     *dto.PackingResponse
+	err        *dto.ErroResponse
+	createdAt  time.Time
+	startedAt  time.Time
+	finishedAt time.Time
+}
+
+// NewJobManager cria o gerenciador e inicia o executor da fila.
+func NewJobManager(svc *PackingService, settings JobSettings) *JobManager {
+	m := newJobManager(svc, settings)
+	go m.run()
+	return m
+}
+
+func newJobManager(svc *PackingService, settings JobSettings) *JobManager {
+	m := &JobManager{
+		service:  svc,
+		settings: settings,
+		now:      time.Now,
+		jobs:     make(map[string]*packingJob),
+	}
+	m.ready = sync.NewCond(&m.mu)
+	return m
+}
+
+// Submit enfileira o lote e devolve o job recém-criado; com a fila cheia, responde 503.
+func (m *JobManager) Submit(req dto.PackingRequest) (dto.JobResponse, error) {
+	id, err := newJobID()
+	if err != nil {
+		return dto.JobResponse{}, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	j := &packingJob{
+		id:     id,
+		req:    req,
+		ctx:    ctx,
+		cancel: cancel,
+		status: dto.JobStatusNaFila,
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.evictExpired()
+
+	if len(m.queue) >= m.settings.QueueSize {
+		cancel()
+		return dto.JobResponse{}, &ServiceError{
+			StatusCode: http.StatusServiceUnavailable,
+			Code:       "JOB_QUEUE_FULL",
+			Message:    "fila de jobs cheia; tente novamente mais tarde",
+		}
+	}
+	j.createdAt = m.now()
+	m.queue = append(m.queue, j)
+	m.jobs[id] = j
+	m.ready.Signal()
+	return m.view(j), nil
+}
+
+// Get devolve o estado atual do job.
+func (m *JobManager) Get(id string) (dto.JobResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.evictExpired()
+
+	j, ok := m.jobs[id]
+	if !ok {
+		return dto.JobResponse{}, jobNotFound(id)
+	}
+	return m.view(j), nil
+}
+
+// Cancel interrompe um job na fila ou em processamento; pedidos já iniciados terminam, os demais não começam.
+// Um job que ainda não começou sai da fila e libera a vaga. Jobs já finalizados não podem ser cancelados (409).
+func (m *JobManager) Cancel(id string) (dto.JobResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.evictExpired()
+
+	j, ok := m.jobs[id]
+	if !ok {
+		return dto.JobResponse{}, jobNotFound(id)
+	}
+	if j.status != dto.JobStatusNaFila && j.status != dto.JobStatusProcessando {
+		return dto.JobResponse{}, &ServiceError{
+			StatusCode: http.StatusConflict,
+			Code:       "JOB_ALREADY_FINISHED",
+			Message:    "job '" + id + "' já foi finalizado com status '" + j.status + "'",
+		}
+	}
+
+	if j.status == dto.JobStatusNaFila {
+		m.dequeue(j)
+	}
+	m.finish(j, dto.JobStatusCancelado)
+	return m.view(j), nil
+}
+
+// run executa os jobs na ordem de chegada, um por vez.
+func (m *JobManager) run() {
+	for {
+		m.process(m.next())
+	}
+}
+
+// next espera e retira o próximo job da fila.
+func (m *JobManager) next() *packingJob {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for len(m.queue) == 0 {
+		m.ready.Wait()
+	}
+	j := m.queue[0]
+	m.queue[0] = nil
+	m.queue = m.queue[1:]
+	return j
+}
+
+// dequeue tira da fila um job que ainda não começou; chamado com m.mu travado.
+func (m *JobManager) dequeue(j *packingJob) {
+	for i, queued := range m.queue {
+		if queued == j {
+			m.queue = append(m.queue[:i], m.queue[i+1:]...)
+			return
+		}
+	}
+}
+
+func (m *JobManager) process(j *packingJob) {
+	m.mu.Lock()
+	if j.status != dto.JobStatusNaFila {
+		// Cancelado entre sair da fila e começar.
+		m.mu.Unlock()
+		return
+	}
+	j.status = dto.JobStatusProcessando
+	j.startedAt = m.now()
+	m.mu.Unlock()
+
+	resp, err := m.service.pack(j.ctx, j.req, func(done int) {
+		m.mu.Lock()
+		j.done = done
+		m.mu.Unlock()
+	}, false)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if j.status != dto.JobStatusProcessando {
+		// Cancel já registrou o estado final.
+		return
+	}
+	if err != nil {
+		j.err = errorBody(err)
+		m.finish(j, dto.JobStatusFalhou)
+		return
+	}
+	j.result = &resp
+	m.finish(j, dto.JobStatusConcluido)
+}
+
+// finish registra o estado final do job e libera o contexto (o que interrompe um lote em andamento);
+// chamado com m.mu travado.
+func (m *JobManager) finish(j *packingJob, status string) {
+	j.status = status
+	j.finishedAt = m.now()
+	j.cancel()
+}
+
+// evictExpired descarta jobs finalizados há mais de ResultTTL; chamado com m.mu travado.
+func (m *JobManager) evictExpired() {
+	now := m.now()
+	for id, j := range m.jobs {
+		if !j.finishedAt.IsZero() && now.Sub(j.finishedAt) > m.settings.ResultTTL {
+			delete(m.jobs, id)
+		}
+	}
+}
+
+// view monta o DTO do job; chamado com m.mu travado.
+func (m *JobManager) view(j *packingJob) dto.JobResponse {
+	total := len(j.req.Pedidos)
+	v := dto.JobResponse{
+		JobID:  j.id,
+		Status: j.status,
+		Progresso: dto.ProgressoJobResponse{
+			PedidosProcessados: j.done,
+			TotalPedidos:       total,
+		},
+		CriadoEm:  j.createdAt,
+		Resultado: j.result,
+		Erro:      j.err,
+	}
+	if total > 0 {
+		v.Progresso.Percentual = float64(j.done) / float64(total) * 100
+	}
+	if !j.startedAt.IsZero() {
+		started := j.startedAt
+		v.IniciadoEm = &started
+	}
+	if !j.finishedAt.IsZero() {
+		finished, expires := j.finishedAt, j.finishedAt.Add(m.settings.ResultTTL)
+		v.FinalizadoEm, v.ExpiraEm = &finished, &expires
+	}
+	return v
+}
+
+func jobNotFound(id string) error {
+	return &ServiceError{
+		StatusCode: http.StatusNotFound,
+		Code:       "JOB_NOT_FOUND",
+		Message:    "job '" + id + "' não encontrado (ou já expirado)",
+	}
+}
+
+func newJobID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/warley004/packing-optimizer-api/internal/api/dto"
+)
+
+func jobRequest(produtos ...dto.ProdutoRequest) dto.PackingRequest {
+	return dto.PackingRequest{Pedidos: []dto.PedidoRequest{{PedidoID: 1, Produtos: produtos}}}
+}
+
+func TestJobManager_ProcessesBatch(t *testing.T) {
+	jobs := NewJobManager(newTestService(t), JobSettings{QueueSize: 4, ResultTTL: time.Minute})
+
+	created, err := jobs.Submit(jobRequest(produto("PS5", 40, 10, 25), produto("Volante", 40, 30, 30)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created.Status != dto.JobStatusNaFila && created.Status != dto.JobStatusProcessando {
+		t.Fatalf("expected new job to be pending, got %s", created.Status)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		job, err := jobs.Get(created.JobID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if job.Status == dto.JobStatusConcluido {
+			if job.Resultado == nil || len(job.Resultado.Pedidos) != 1 {
+				t.Fatalf("expected result with 1 order, got %+v", job.Resultado)
+			}
+			if job.Progresso.PedidosProcessados != 1 || job.Progresso.Percentual != 100 || job.ExpiraEm == nil {
+				t.Fatalf("expected complete progress and expiry, got %+v", job)
+			}
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job did not finish, last status %s", job.Status)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestJobManager_QueueLimitAndCancel(t *testing.T) {
+	// Sem executor: os jobs ficam na fila até process ser chamado.
+	jobs := newJobManager(newTestService(t), JobSettings{QueueSize: 1, ResultTTL: time.Minute})
+
+	queued, err := jobs.Submit(jobRequest(produto("PS5", 40, 10, 25)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = jobs.Submit(jobRequest(produto("PS5", 40, 10, 25)))
+	var se *ServiceError
+	if !errors.As(err, &se) || se.StatusCode != http.StatusServiceUnavailable || se.Code != "JOB_QUEUE_FULL" {
+		t.Fatalf("expected queue full error, got %v", err)
+	}
+
+	canceled, err := jobs.Cancel(queued.JobID)
+	if err != nil || canceled.Status != dto.JobStatusCancelado {
+		t.Fatalf("expected canceled job, got %+v (err=%v)", canceled, err)
+	}
+	if _, err := jobs.Cancel(queued.JobID); !errors.As(err, &se) || se.StatusCode != http.StatusConflict {
+		t.Fatalf("expected conflict when canceling a finished job, got %v", err)
+	}
+
+	// O job cancelado sai da fila e libera a vaga para o próximo.
+	next, err := jobs.Submit(jobRequest(produto("PS5", 40, 10, 25)))
+	if err != nil {
+		t.Fatalf("expected the canceled job to free its queue slot, got %v", err)
+	}
+	if j := jobs.next(); j.id != next.JobID {
+		t.Fatalf("expected the executor to get job %s, got %s", next.JobID, j.id)
+	}
+	if job, _ := jobs.Get(queued.JobID); job.Status != dto.JobStatusCancelado || job.IniciadoEm != nil {
+		t.Fatalf("expected canceled job to never start, got %+v", job)
+	}
+}
+
+func TestJobManager_ReportsFailureAndExpires(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	jobs := newJobManager(newTestService(t), JobSettings{QueueSize: 1, ResultTTL: time.Hour})
+	jobs.now = func() time.Time { return now }
+
+	created, err := jobs.Submit(jobRequest(produto("Geladeira", 500, 500, 500)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	jobs.process(jobs.next())
+
+	job, err := jobs.Get(created.JobID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if job.Status != dto.JobStatusFalhou || job.Erro == nil || job.Erro.Code != "PACKING_ERROR" || job.Resultado != nil {
+		t.Fatalf("expected failed job with packing error, got %+v", job)
+	}
+
+	now = now.Add(2 * time.Hour)
+	var se *ServiceError
+	if _, err := jobs.Get(created.JobID); !errors.As(err, &se) || se.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected expired job to be gone, got %v", err)
+	}
+}
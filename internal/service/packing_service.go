@@ -1,131 +1,416 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
-	"runtime"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/warley004/packing-optimizer-api/internal/api/dto"
+	"github.com/warley004/packing-optimizer-api/internal/catalog"
+	"github.com/warley004/packing-optimizer-api/internal/metrics"
 	"github.com/warley004/packing-optimizer-api/internal/packing"
 )
 
 type PackingService struct {
-	boxes []packing.BoxType
+	catalog  catalog.Repository
+	settings Settings
+	pool     *workerPool
+}
+
+// Settings reúne parâmetros de execução do serviço definidos na inicialização.
+type Settings struct {
+	// ExactMaxItems habilita a busca exata para pedidos com até esse número de produtos; zero usa só a heurística.
+	ExactMaxItems int
+	// RequestTimeout limita o tempo de um POST /v1/packing síncrono; zero desliga o limite.
+	// Jobs assíncronos e o streaming não usam esse prazo.
+	RequestTimeout time.Duration
+	// PoolWorkers é o número de workers compartilhados por todos os requests; zero usa runtime.NumCPU().
+	PoolWorkers int
+	// PoolQueueSize limita os pedidos aguardando workers somando todos os requests; zero usa o padrão (1024).
+	PoolQueueSize int
+	// Metrics recebe as medições de empacotamento e do pool; nil desliga a coleta.
+	Metrics *metrics.Metrics
 }
 
 // Service consolida regras de domínio de empacotamento; handlers apenas transformam HTTP <-> DTO e delegam aqui.
-func NewPackingService() *PackingService {
-	return &PackingService{
-		boxes: packing.AvailableBoxes(),
+// O catálogo é consultado a cada request, então caixas criadas/aposentadas via /v1/boxes valem imediatamente.
+func NewPackingService(repo catalog.Repository, settings Settings) *PackingService {
+	s := &PackingService{
+		catalog:  repo,
+		settings: settings,
+		pool:     newWorkerPool(settings.PoolWorkers, settings.PoolQueueSize),
 	}
+	settings.Metrics.RegisterPool(s.pool.stats)
+	return s
+}
+
+// Close encerra o pool de workers: pedidos já na fila terminam e novos lotes são recusados (503).
+// Chamado no desligamento da API, depois que o servidor HTTP parou de aceitar requests.
+func (s *PackingService) Close() {
+	s.pool.close()
 }
 
 type ServiceError struct {
 	StatusCode int
-	Message    string
+	// Code é o código exposto na API; vazio equivale a PACKING_ERROR.
+	Code    string
+	Message string
 }
 
 func (e *ServiceError) Error() string {
 	return e.Message
 }
 
-func (s *PackingService) Pack(req dto.PackingRequest) (dto.PackingResponse, error) {
-	total := len(req.Pedidos)
-	resp := dto.PackingResponse{
-		Pedidos: make([]dto.PedidoResponse, total),
+// ErrorCode devolve o código exposto na API, aplicando o padrão PACKING_ERROR.
+func (e *ServiceError) ErrorCode() string {
+	if e.Code == "" {
+		return "PACKING_ERROR"
+	}
+	return e.Code
+}
+
+// orderOptions carrega os parâmetros do request que valem para todos os pedidos do lote.
+type orderOptions struct {
+	// catalog é o catálogo ativo (base para caixas_permitidas); boxes é o conjunto padrão do lote, já com a sobrescrita do request.
+	catalog         []packing.BoxType
+	boxes           []packing.BoxType
+	packing         packing.Options
+	incluirPosicoes bool
+	// incluirEspacosLivres pede a lista de espaços livres de cada caixa.
+	incluirEspacosLivres bool
+	// folgaPadrao vale para produtos sem folga própria.
+	folgaPadrao int
+	// costModel é preenchido com objetivo "custo" para reportar o custo estimado.
+	costModel *packing.CostModel
+}
+
+func newOrderOptions(req dto.PackingRequest, catalogBoxes, boxes []packing.BoxType) orderOptions {
+	opts := orderOptions{
+		catalog: catalogBoxes,
+		boxes:   boxes,
+		packing: packing.Options{
+			AllowRotation: true,
+			FreeSpace:     freeSpaceStrategy(req.EstrategiaEspacos),
+			Packer:        packer(req.Algoritmo),
+			AllowPartial:  req.PermitirParcial,
+			// apoio_minimo chega em percentual; o empacotamento trabalha com fração da base.
+			MinSupportRatio: req.ApoioMinimo / 100,
+			Balance:         req.Balancear,
+		},
+		incluirPosicoes:      req.IncluirPosicoes,
+		incluirEspacosLivres: req.IncluirEspacosLivres,
+		folgaPadrao:          req.FolgaPadrao,
 	}
 
-	if total == 0 {
-		return resp, nil
+	if req.Objetivo == "custo" {
+		model := packing.CostModel{}
+		if m := req.ModeloCusto; m != nil {
+			model = packing.CostModel{
+				DimWeightDivisor:  m.DivisorPesoCubado,
+				RatePerKg:         m.ValorPorKg,
+				VoidFillPerVolume: m.CustoPreenchimento,
+			}
+		}
+		opts.packing.Objective = model
+		opts.costModel = &model
 	}
 
-	type job struct {
-		index  int
-		pedido dto.PedidoRequest
+	return opts
+}
+
+func freeSpaceStrategy(estrategia string) packing.FreeSpaceStrategy {
+	if estrategia == "espacos_maximais" {
+		return packing.MaximalSpaces
 	}
+	return packing.GuillotineSplit
+}
 
-	type result struct {
-		index  int
-		pedido dto.PedidoResponse
-		err    error
+func packer(algoritmo string) packing.Packer {
+	switch algoritmo {
+	case "bfd":
+		return packing.BestFitDecreasing{}
+	case "camadas":
+		return packing.LayerPacker{}
+	case "melhor":
+		return packing.BestOf{}
 	}
+	return packing.FirstFitDecreasing{}
+}
 
-	jobCh := make(chan job)
-	resultCh := make(chan result, total)
+func orientation(orientacao string) packing.Orientation {
+	switch orientacao {
+	case "vertical":
+		return packing.OrientationUpright
+	case "fixa":
+		return packing.OrientationFixed
+	case "livre":
+		return packing.OrientationFree
+	}
+	return packing.OrientationDefault
+}
 
-	workerCount := runtime.NumCPU()
-	if workerCount > total {
-		workerCount = total
+// Pack empacota o lote respeitando o contexto do request (cliente desconectado) e o prazo RequestTimeout.
+// Estourado o prazo, a busca em andamento é interrompida e o erro é PACKING_TIMEOUT (504).
+func (s *PackingService) Pack(ctx context.Context, req dto.PackingRequest) (dto.PackingResponse, error) {
+	if s.settings.RequestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.settings.RequestTimeout)
+		defer cancel()
 	}
-	if workerCount < 1 {
-		workerCount = 1
+
+	resp, err := s.pack(ctx, req, nil, true)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return dto.PackingResponse{}, &ServiceError{
+			StatusCode: http.StatusGatewayTimeout,
+			Code:       "PACKING_TIMEOUT",
+			Message: fmt.Sprintf("o empacotamento excedeu o tempo limite de %s; divida o lote ou use /v1/packing/jobs",
+				s.settings.RequestTimeout),
+		}
 	}
+	return resp, err
+}
 
-	var wg sync.WaitGroup
-	wg.Add(workerCount)
-
-	for i := 0; i < workerCount; i++ {
-		go func() {
-			defer wg.Done()
-			for j := range jobCh {
-				pedidoResp, err := s.packSingleOrder(j.pedido)
-				resultCh <- result{index: j.index, pedido: pedidoResp, err: err}
-			}
-		}()
+// pack processa o lote no pool de workers. progress, quando informado, recebe o número de pedidos já processados
+// a cada pedido concluído. Com o contexto cancelado, nenhum pedido novo é iniciado e o erro do contexto é devolvido.
+//
+// Com shed, o lote reserva vagas na fila do pool (a fila inteira, se for maior que ela) e é recusado com 503 se a
+// fila está ocupada por outros requests (requests síncronos); sem shed, os pedidos entram aos poucos, no máximo um
+// por worker à frente, esperando por espaço (jobs assíncronos).
+func (s *PackingService) pack(ctx context.Context, req dto.PackingRequest, progress func(done int), shed bool) (dto.PackingResponse, error) {
+	total := len(req.Pedidos)
+	resp := dto.PackingResponse{
+		Pedidos: make([]dto.PedidoResponse, total),
 	}
 
+	if total == 0 {
+		return resp, nil
+	}
+
+	opts, err := s.batchOptions(req)
+	if err != nil {
+		return dto.PackingResponse{}, err
+	}
+
+	l, inflight := s.pool.newLane(), s.pool.workers
+	if shed {
+		if l, err = s.pool.reserve(total); err != nil {
+			return dto.PackingResponse{}, err
+		}
+		inflight = total
+	}
+	defer l.release()
+
+	jobCh := make(chan orderJob)
 	go func() {
+		defer close(jobCh)
 		for idx, pedido := range req.Pedidos {
-			jobCh <- job{index: idx, pedido: pedido}
+			select {
+			case jobCh <- orderJob{index: idx, pedido: pedido}:
+			case <-ctx.Done():
+				return
+			}
 		}
-		close(jobCh)
 	}()
 
-	go func() {
-		wg.Wait()
-		close(resultCh)
-	}()
+	resultCh := s.runOrders(ctx, l, inflight, jobCh, opts)
 
-	errors := make([]error, total)
+	errs := make([]error, total)
+	done := 0
 	for res := range resultCh {
+		done++
+		if progress != nil {
+			progress(done)
+		}
 		if res.err != nil {
-			errors[res.index] = res.err
+			errs[res.index] = res.err
 			continue
 		}
 		resp.Pedidos[res.index] = res.pedido
 	}
+	if err := ctx.Err(); err != nil {
+		return dto.PackingResponse{}, err
+	}
 
+	bestEffort := req.Modo == "melhor_esforco"
 	for idx := 0; idx < total; idx++ {
-		if errors[idx] != nil {
-			return dto.PackingResponse{}, errors[idx]
+		if errs[idx] == nil {
+			continue
+		}
+		if !bestEffort {
+			return dto.PackingResponse{}, errs[idx]
 		}
+		resp.Pedidos[idx] = failedOrder(req.Pedidos[idx].PedidoID, errs[idx])
+		resp.PedidosComErro++
 	}
 
 	return resp, nil
 }
 
+// batchOptions tira um snapshot do catálogo e resolve as opções que valem para todos os pedidos do request:
+// todos usam o mesmo conjunto de caixas mesmo que o catálogo mude durante o processamento.
+func (s *PackingService) batchOptions(req dto.PackingRequest) (orderOptions, error) {
+	catalogBoxes, err := catalog.ActiveBoxes(s.catalog)
+	if err != nil {
+		return orderOptions{}, fmt.Errorf("falha ao ler catálogo de caixas: %w", err)
+	}
+	boxes, err := resolveBoxes(catalogBoxes, req.CaixasPermitidas, req.Caixas)
+	if err != nil {
+		return orderOptions{}, err
+	}
+	opts := newOrderOptions(req, catalogBoxes, boxes)
+	opts.packing.ExactMaxItems = s.settings.ExactMaxItems
+	return opts, nil
+}
+
+// orderJob é um pedido a empacotar; index é sua posição no lote. err, quando preenchido, já rejeita o pedido
+// (ex.: linha inválida no streaming) e o worker só repassa a falha.
+type orderJob struct {
+	index  int
+	pedido dto.PedidoRequest
+	err    error
+}
+
+type orderResult struct {
+	index    int
+	pedidoID int64
+	pedido   dto.PedidoResponse
+	err      error
+}
+
+// runOrders despacha os pedidos recebidos em jobs para a lane no pool compartilhado e publica os resultados no
+// canal devolvido, fechado quando jobs se esgota e todos os pedidos despachados terminam. No máximo inflight pedidos
+// ficam entre o despacho e a entrega ao consumidor: os workers do pool nunca esperam por um consumidor lento.
+// Com o contexto cancelado, a busca em andamento é interrompida, os pedidos na fila são descartados e nada mais
+// é publicado.
+func (s *PackingService) runOrders(ctx context.Context, l *lane, inflight int, jobs <-chan orderJob, opts orderOptions) <-chan orderResult {
+	inflight = max(inflight, 1)
+	slots := make(chan struct{}, inflight)
+	done := make(chan orderResult, inflight)
+	results := make(chan orderResult)
+
+	var wg sync.WaitGroup
+	go func() {
+		defer func() {
+			wg.Wait()
+			close(done)
+		}()
+		for j := range jobs {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			wg.Add(1)
+			task := func() {
+				defer wg.Done()
+				if ctx.Err() != nil {
+					return
+				}
+				res := orderResult{index: j.index, pedidoID: j.pedido.PedidoID, err: j.err}
+				if res.err == nil {
+					res.pedido, res.err = s.packSingleOrder(ctx, j.pedido, opts)
+				}
+				if ctx.Err() != nil {
+					// O pedido pode ter sido interrompido no meio: o resultado não vale.
+					return
+				}
+				done <- res
+			}
+			if err := l.submit(ctx, task); err != nil {
+				wg.Done()
+				return
+			}
+		}
+	}()
+
+	go func() {
+		defer close(results)
+		for res := range done {
+			select {
+			case results <- res:
+				<-slots
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return results
+}
+
+// failedOrder representa no lote um pedido que não pôde ser empacotado (modo "melhor_esforco").
+func failedOrder(pedidoID int64, err error) dto.PedidoResponse {
+	return dto.PedidoResponse{
+		PedidoID: pedidoID,
+		Caixas:   []dto.CaixaResponse{},
+		Status:   dto.PedidoStatusErro,
+		Erro:     errorBody(err),
+	}
+}
+
+// errorBody converte o erro no corpo de erro padrão da API; erros não mapeados viram INTERNAL_ERROR.
+func errorBody(err error) *dto.ErroResponse {
+	var se *ServiceError
+	if errors.As(err, &se) {
+		return &dto.ErroResponse{Code: se.ErrorCode(), Message: se.Message}
+	}
+	return &dto.ErroResponse{Code: "INTERNAL_ERROR", Message: "erro interno inesperado"}
+}
+
 func formatID(id int64) string {
 	return fmt.Sprintf("%d", id)
 }
 
-func (s *PackingService) packSingleOrder(pedido dto.PedidoRequest) (dto.PedidoResponse, error) {
+func (s *PackingService) packSingleOrder(ctx context.Context, pedido dto.PedidoRequest, opts orderOptions) (dto.PedidoResponse, error) {
 	items := make([]packing.Item, 0, len(pedido.Produtos))
 	for idx, p := range pedido.Produtos {
-		items = append(items, packing.Item{
+		item := packing.Item{
 			ProductID: p.ProdutoID,
 			Dim: packing.Dimensions{
 				Height: p.Dimensoes.Altura,
 				Width:  p.Dimensoes.Largura,
 				Length: p.Dimensoes.Comprimento,
 			},
-			Index: idx, // preserva ordem do input
-		})
+			Index:        idx, // preserva ordem do input
+			Weight:       p.Peso,
+			Orientation:  orientation(p.Orientacao),
+			Clearance:    opts.folgaPadrao,
+			Fragile:      p.Fragil,
+			MaxLoadOnTop: p.CargaMaximaTopo,
+		}
+		if p.Folga != nil {
+			item.Clearance = *p.Folga
+		}
+		// Cada unidade vira um item; unidades idênticas ficam adjacentes e são alocadas em blocos pelo domínio.
+		for range max(p.Quantidade, 1) {
+			items = append(items, item)
+		}
+	}
+
+	boxes := opts.boxes
+	if len(pedido.CaixasPermitidas) > 0 || len(pedido.Caixas) > 0 {
+		var err error
+		boxes, err = resolveBoxes(opts.catalog, pedido.CaixasPermitidas, pedido.Caixas)
+		if err != nil {
+			return dto.PedidoResponse{}, withOrderPrefix(pedido.PedidoID, err)
+		}
 	}
 
-	result, err := packing.PackOrder(items, s.boxes, true)
+	start := time.Now()
+	result, err := packing.PackOrderContext(ctx, items, boxes, opts.packing)
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return dto.PedidoResponse{}, err
+	}
+	s.settings.Metrics.ObservePackDuration(time.Since(start))
 	if err != nil {
+		var ue *packing.UnpackableError
+		if errors.As(err, &ue) {
+			s.settings.Metrics.ObserveUnpackable(unpackedReason(ue.Reason), 1)
+		}
 		return dto.PedidoResponse{}, &ServiceError{
 			StatusCode: http.StatusUnprocessableEntity,
 			Message:    "Pedido " + formatID(pedido.PedidoID) + ": " + err.Error(),
@@ -133,25 +418,169 @@ func (s *PackingService) packSingleOrder(pedido dto.PedidoRequest) (dto.PedidoRe
 	}
 
 	pr := dto.PedidoResponse{
-		PedidoID: pedido.PedidoID,
-		Caixas:   make([]dto.CaixaResponse, 0, len(result.Boxes)),
+		PedidoID:        pedido.PedidoID,
+		Caixas:          make([]dto.CaixaResponse, 0, len(result.Boxes)),
+		Status:          dto.PedidoStatusOK,
+		OtimoComprovado: result.Optimal,
 	}
 
 	for _, b := range result.Boxes {
-		sort.Slice(b.Products, func(i, j int) bool {
+		sort.SliceStable(b.Products, func(i, j int) bool {
 			return b.Products[i].Index < b.Products[j].Index
 		})
 
+		// Unidades de um mesmo produto do pedido compartilham o Index: aparecem uma vez em Produtos e somam em Quantidades.
 		ids := make([]string, 0, len(b.Products))
-		for _, pp := range b.Products {
-			ids = append(ids, pp.ID)
+		quantidades := make(map[string]int)
+		for i, pp := range b.Products {
+			if i == 0 || pp.Index != b.Products[i-1].Index {
+				ids = append(ids, pp.ID)
+			}
+			quantidades[pp.ID]++
 		}
 
-		pr.Caixas = append(pr.Caixas, dto.CaixaResponse{
-			CaixaID:  b.BoxType.ID,
-			Produtos: ids,
+		outer := b.BoxType.OuterDimensions()
+		cg := b.CenterOfGravity()
+		caixa := dto.CaixaResponse{
+			CaixaID:     b.BoxType.ID,
+			Produtos:    ids,
+			Quantidades: quantidades,
+			DimensoesExternas: dto.DimensoesDTO{
+				Altura:      outer.Height,
+				Largura:     outer.Width,
+				Comprimento: outer.Length,
+			},
+			VolumeExterno:      outer.Volume(),
+			VolumeCaixa:        b.BoxType.InnerDimensions().Volume(),
+			VolumeUsado:        b.UsedVolume(),
+			OcupacaoPercentual: b.FillRate() * 100,
+			PesoBruto:          b.GrossWeight(),
+			CentroGravidade:    dto.PontoDTO{X: cg.X, Y: cg.Y, Z: cg.Z},
+		}
+		if opts.costModel != nil {
+			cost := opts.costModel.BoxCost(b)
+			caixa.CustoEstimado = &cost
+		}
+		if opts.incluirPosicoes {
+			caixa.Posicoes = toPosicoes(b, pedido.Produtos)
+		}
+		if opts.incluirEspacosLivres {
+			caixa.EspacosLivres = toEspacosLivres(b)
+		}
+
+		pr.Caixas = append(pr.Caixas, caixa)
+		pr.Resumo.VolumeTotal += caixa.VolumeCaixa
+		pr.Resumo.VolumeUsado += caixa.VolumeUsado
+		pr.Resumo.OcupacaoMedia += caixa.OcupacaoPercentual
+	}
+	pr.Resumo.TotalCaixas = len(pr.Caixas)
+	if pr.Resumo.TotalCaixas > 0 {
+		pr.Resumo.OcupacaoMedia /= float64(pr.Resumo.TotalCaixas)
+	}
+	lb := result.LowerBound
+	pr.Resumo.LimiteInferior = lb.Value
+	pr.Resumo.LimitesInferiores = dto.LimitesInferioresResponse{
+		Volume:            lb.Volume,
+		Peso:              lb.Weight,
+		Incompatibilidade: lb.Incompatible,
+		Dimensional:       lb.Dimensional,
+	}
+	pr.Resumo.Gap = lb.Gap(pr.Resumo.TotalCaixas)
+	if lb.Value > 0 {
+		pr.Resumo.GapPercentual = float64(pr.Resumo.Gap) / float64(lb.Value) * 100
+	}
+
+	if opts.costModel != nil {
+		total := result.Cost(*opts.costModel)
+		pr.CustoEstimado = &total
+	}
+
+	for i, u := range result.Unpacked {
+		// Unpacked vem na ordem do pedido: unidades do mesmo produto são agregadas em uma entrada.
+		if i > 0 && u.Item.Index == result.Unpacked[i-1].Item.Index {
+			pr.NaoEmpacotados[len(pr.NaoEmpacotados)-1].Quantidade++
+			continue
+		}
+		pr.NaoEmpacotados = append(pr.NaoEmpacotados, dto.ProdutoNaoEmpacotadoResponse{
+			ProdutoID:  u.Item.ProductID,
+			Quantidade: 1,
+			Motivo:     unpackedReason(u.Reason),
+			Mensagem:   u.Message,
 		})
 	}
+	if len(pr.NaoEmpacotados) > 0 {
+		pr.Status = dto.PedidoStatusParcial
+	}
+
+	fillRates := make([]float64, len(result.Boxes))
+	for i := range result.Boxes {
+		fillRates[i] = result.Boxes[i].FillRate()
+	}
+	s.settings.Metrics.ObserveOrder(len(items), fillRates)
+	for _, np := range pr.NaoEmpacotados {
+		s.settings.Metrics.ObserveUnpackable(np.Motivo, np.Quantidade)
+	}
 
 	return pr, nil
 }
+
+func unpackedReason(reason packing.UnpackedReason) string {
+	switch reason {
+	case packing.ReasonOverWeight:
+		return "excede_peso"
+	case packing.ReasonOrientationLocked:
+		return "orientacao_bloqueada"
+	}
+	return "muito_grande"
+}
+
+// toPosicoes converte a alocação do domínio em coordenadas; a orientação é comparada com as dimensões do input para sinalizar rotação.
+func toPosicoes(b packing.PackedBox, produtos []dto.ProdutoRequest) []dto.PosicaoProdutoResponse {
+	posicoes := make([]dto.PosicaoProdutoResponse, 0, len(b.Products))
+	for _, pp := range b.Products {
+		original := produtos[pp.Index].Dimensoes
+		pos, rot := pp.ProductPosition(), pp.ProductDimensions()
+		posicao := dto.PosicaoProdutoResponse{
+			ProdutoID: pp.ID,
+			Posicao: dto.CoordenadasDTO{
+				X: pos.X,
+				Y: pos.Y,
+				Z: pos.Z,
+			},
+			Orientacao: dto.DimensoesDTO{
+				Altura:      rot.Height,
+				Largura:     rot.Width,
+				Comprimento: rot.Length,
+			},
+			Rotacionado: rot.Height != original.Altura ||
+				rot.Width != original.Largura ||
+				rot.Length != original.Comprimento,
+		}
+		if c := pp.Clearance(); c > 0 {
+			posicao.Folga = c
+			posicao.DimensoesComFolga = &dto.DimensoesDTO{
+				Altura:      pp.Rotation.Height,
+				Largura:     pp.Rotation.Width,
+				Comprimento: pp.Rotation.Length,
+			}
+		}
+		posicoes = append(posicoes, posicao)
+	}
+	return posicoes
+}
+
+func toEspacosLivres(b packing.PackedBox) []dto.EspacoLivreResponse {
+	spaces := b.FreeSpaces()
+	espacos := make([]dto.EspacoLivreResponse, 0, len(spaces))
+	for _, sp := range spaces {
+		espacos = append(espacos, dto.EspacoLivreResponse{
+			Posicao: dto.CoordenadasDTO{X: sp.Origin.X, Y: sp.Origin.Y, Z: sp.Origin.Z},
+			Dimensoes: dto.DimensoesDTO{
+				Altura:      sp.Dim.Height,
+				Largura:     sp.Dim.Width,
+				Comprimento: sp.Dim.Length,
+			},
+		})
+	}
+	return espacos
+}
@@ -0,0 +1,424 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/warley004/packing-optimizer-api/internal/api/dto"
+	"github.com/warley004/packing-optimizer-api/internal/catalog"
+	"github.com/warley004/packing-optimizer-api/internal/metrics"
+	"github.com/warley004/packing-optimizer-api/internal/packing"
+)
+
+// newTestService cria o serviço sobre o catálogo padrão; configure ajusta os Settings a partir de ExactMaxItems 8.
+func newTestService(t *testing.T, configure ...func(*Settings)) *PackingService {
+	t.Helper()
+	repo := catalog.NewMemoryRepository()
+	if _, err := catalog.Seed(repo, packing.AvailableBoxes()); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+	settings := Settings{ExactMaxItems: 8}
+	for _, c := range configure {
+		c(&settings)
+	}
+	svc := NewPackingService(repo, settings)
+	t.Cleanup(svc.Close)
+	return svc
+}
+
+func produto(id string, altura, largura, comprimento int) dto.ProdutoRequest {
+	return dto.ProdutoRequest{
+		ProdutoID: id,
+		Dimensoes: dto.DimensoesDTO{Altura: altura, Largura: largura, Comprimento: comprimento},
+	}
+}
+
+func caixaInline(id string, altura, largura, comprimento int) dto.CaixaRequest {
+	return dto.CaixaRequest{
+		CaixaID: id,
+		CaixaDadosDTO: dto.CaixaDadosDTO{
+			Dimensoes: dto.DimensoesDTO{Altura: altura, Largura: largura, Comprimento: comprimento},
+		},
+	}
+}
+
+func serviceErrorStatus(t *testing.T, err error) int {
+	t.Helper()
+	var se *ServiceError
+	if !errors.As(err, &se) {
+		t.Fatalf("expected ServiceError, got %v", err)
+	}
+	return se.StatusCode
+}
+
+func TestPack_OrderAllowedBoxesRestrictCatalog(t *testing.T) {
+	svc := newTestService(t)
+
+	resp, err := svc.Pack(context.Background(), dto.PackingRequest{Pedidos: []dto.PedidoRequest{{
+		PedidoID:         1,
+		Produtos:         []dto.ProdutoRequest{produto("Livro", 5, 10, 10)},
+		CaixasPermitidas: []string{"Caixa 3"},
+	}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := resp.Pedidos[0].Caixas[0].CaixaID; got != "Caixa 3" {
+		t.Fatalf("expected Caixa 3, got %s", got)
+	}
+}
+
+func TestPack_InlineBoxesOverrideRequestLevelBoxes(t *testing.T) {
+	svc := newTestService(t)
+
+	resp, err := svc.Pack(context.Background(), dto.PackingRequest{
+		CaixasPermitidas: []string{"Caixa 2"},
+		Pedidos: []dto.PedidoRequest{
+			{PedidoID: 1, Produtos: []dto.ProdutoRequest{produto("A", 5, 10, 10)}},
+			{
+				PedidoID: 2,
+				Produtos: []dto.ProdutoRequest{produto("B", 5, 10, 10)},
+				Caixas:   []dto.CaixaRequest{caixaInline("Marketplace", 10, 10, 10)},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := resp.Pedidos[0].Caixas[0].CaixaID; got != "Caixa 2" {
+		t.Fatalf("expected request-level Caixa 2, got %s", got)
+	}
+	if got := resp.Pedidos[1].Caixas[0].CaixaID; got != "Marketplace" {
+		t.Fatalf("expected inline Marketplace box, got %s", got)
+	}
+}
+
+func TestPack_BoxOverrideValidation(t *testing.T) {
+	svc := newTestService(t)
+	cases := map[string]struct {
+		pedido dto.PedidoRequest
+		status int
+	}{
+		"unknown box id": {dto.PedidoRequest{
+			PedidoID: 1, Produtos: []dto.ProdutoRequest{produto("A", 1, 1, 1)}, CaixasPermitidas: []string{"Caixa 9"},
+		}, http.StatusUnprocessableEntity},
+		"both forms": {dto.PedidoRequest{
+			PedidoID: 1, Produtos: []dto.ProdutoRequest{produto("A", 1, 1, 1)}, CaixasPermitidas: []string{"Caixa 1"},
+			Caixas: []dto.CaixaRequest{caixaInline("X", 1, 1, 1)},
+		}, http.StatusBadRequest},
+		"duplicated inline id": {dto.PedidoRequest{
+			PedidoID: 1, Produtos: []dto.ProdutoRequest{produto("A", 1, 1, 1)},
+			Caixas: []dto.CaixaRequest{
+				caixaInline("X", 1, 1, 1),
+				caixaInline("X", 2, 2, 2),
+			},
+		}, http.StatusBadRequest},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := svc.Pack(context.Background(), dto.PackingRequest{Pedidos: []dto.PedidoRequest{tc.pedido}})
+			if got := serviceErrorStatus(t, err); got != tc.status {
+				t.Fatalf("expected status %d, got %d (%v)", tc.status, got, err)
+			}
+		})
+	}
+}
+
+func TestPack_CostObjectiveReportsEstimatedCost(t *testing.T) {
+	svc := newTestService(t)
+	barata := caixaInline("Barata", 20, 20, 20)
+	barata.Custo = 2
+	cara := caixaInline("Cara", 10, 10, 10)
+	cara.Custo = 10
+
+	resp, err := svc.Pack(context.Background(), dto.PackingRequest{
+		Objetivo: "custo",
+		Caixas:   []dto.CaixaRequest{cara, barata},
+		Pedidos:  []dto.PedidoRequest{{PedidoID: 1, Produtos: []dto.ProdutoRequest{produto("A", 10, 10, 10)}}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	pedido := resp.Pedidos[0]
+	if pedido.Caixas[0].CaixaID != "Barata" {
+		t.Fatalf("expected Barata, got %s", pedido.Caixas[0].CaixaID)
+	}
+	if pedido.CustoEstimado == nil || *pedido.CustoEstimado != 2 {
+		t.Fatalf("expected estimated cost 2, got %v", pedido.CustoEstimado)
+	}
+}
+
+func TestPack_BestEffortReportsFailedOrders(t *testing.T) {
+	svc := newTestService(t)
+	pedidos := []dto.PedidoRequest{
+		{PedidoID: 1, Produtos: []dto.ProdutoRequest{produto("Livro", 5, 10, 10)}},
+		{PedidoID: 2, Produtos: []dto.ProdutoRequest{produto("Geladeira", 200, 100, 100)}},
+	}
+
+	if _, err := svc.Pack(context.Background(), dto.PackingRequest{Pedidos: pedidos}); err == nil {
+		t.Fatalf("expected fail-fast error by default")
+	}
+
+	resp, err := svc.Pack(context.Background(), dto.PackingRequest{Pedidos: pedidos, Modo: "melhor_esforco"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.PedidosComErro != 1 {
+		t.Fatalf("expected 1 failed order, got %d", resp.PedidosComErro)
+	}
+
+	ok, failed := resp.Pedidos[0], resp.Pedidos[1]
+	if ok.Status != dto.PedidoStatusOK || len(ok.Caixas) != 1 {
+		t.Fatalf("expected order 1 packed, got %+v", ok)
+	}
+	if failed.PedidoID != 2 || failed.Status != dto.PedidoStatusErro || failed.Erro == nil || failed.Erro.Code != "PACKING_ERROR" {
+		t.Fatalf("expected order 2 reported as PACKING_ERROR, got %+v", failed)
+	}
+	if len(failed.Caixas) != 0 {
+		t.Fatalf("expected no boxes for failed order, got %d", len(failed.Caixas))
+	}
+}
+
+func TestPack_QuantityReportsUnitsPerBox(t *testing.T) {
+	svc := newTestService(t)
+	caneca := produto("Caneca", 10, 10, 10)
+	caneca.Quantidade = 200
+
+	resp, err := svc.Pack(context.Background(), dto.PackingRequest{Pedidos: []dto.PedidoRequest{{
+		PedidoID: 1,
+		Produtos: []dto.ProdutoRequest{caneca, produto("Livro", 5, 10, 10)},
+		Caixas:   []dto.CaixaRequest{caixaInline("Cubo", 50, 50, 50)},
+	}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	caixas := resp.Pedidos[0].Caixas
+	if len(caixas) != 2 {
+		t.Fatalf("expected 2 boxes (125 + 75 mugs and the book), got %d", len(caixas))
+	}
+
+	total := 0
+	for _, c := range caixas {
+		total += c.Quantidades["Caneca"]
+		if len(c.Produtos) > 2 {
+			t.Fatalf("expected each product listed once per box, got %v", c.Produtos)
+		}
+	}
+	if total != 200 {
+		t.Fatalf("expected 200 mugs across boxes, got %d", total)
+	}
+	if caixas[0].Quantidades["Caneca"] != 125 {
+		t.Fatalf("expected first box full with 125 mugs, got %d", caixas[0].Quantidades["Caneca"])
+	}
+}
+
+func TestPack_ClearanceReportsRealAndPaddedDimensions(t *testing.T) {
+	svc := newTestService(t)
+	semFolga := 0
+	vaso := produto("Vaso", 10, 10, 10)
+	livro := produto("Livro", 10, 10, 10)
+	livro.Folga = &semFolga
+
+	resp, err := svc.Pack(context.Background(), dto.PackingRequest{
+		FolgaPadrao:     2,
+		IncluirPosicoes: true,
+		Pedidos:         []dto.PedidoRequest{{PedidoID: 1, Produtos: []dto.ProdutoRequest{vaso, livro}}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	posicoes := resp.Pedidos[0].Caixas[0].Posicoes
+	if len(posicoes) != 2 {
+		t.Fatalf("expected 2 positions, got %d", len(posicoes))
+	}
+	for _, p := range posicoes {
+		if p.Orientacao != (dto.DimensoesDTO{Altura: 10, Largura: 10, Comprimento: 10}) {
+			t.Fatalf("expected real dimensions for %s, got %+v", p.ProdutoID, p.Orientacao)
+		}
+		switch p.ProdutoID {
+		case "Vaso":
+			if p.Folga != 2 || p.DimensoesComFolga == nil || p.DimensoesComFolga.Altura != 14 {
+				t.Fatalf("expected default clearance 2 with 14 padded, got %+v", p)
+			}
+		case "Livro":
+			if p.Folga != 0 || p.DimensoesComFolga != nil {
+				t.Fatalf("expected explicit zero clearance, got %+v", p)
+			}
+		}
+	}
+}
+
+func TestPack_ReportsFillMetrics(t *testing.T) {
+	svc := newTestService(t)
+
+	resp, err := svc.Pack(context.Background(), dto.PackingRequest{
+		IncluirEspacosLivres: true,
+		Caixas:               []dto.CaixaRequest{caixaInline("Cubo", 10, 10, 10)},
+		Pedidos: []dto.PedidoRequest{{PedidoID: 1, Produtos: []dto.ProdutoRequest{
+			produto("Base", 5, 10, 10),
+			produto("Bloco", 10, 10, 10),
+		}}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	pedido := resp.Pedidos[0]
+	want := dto.ResumoPedidoResponse{
+		TotalCaixas:       2,
+		VolumeTotal:       2000,
+		VolumeUsado:       1500,
+		OcupacaoMedia:     75,
+		LimiteInferior:    2,
+		LimitesInferiores: dto.LimitesInferioresResponse{Volume: 2, Incompatibilidade: 2, Dimensional: 2},
+	}
+	if pedido.Resumo != want {
+		t.Fatalf("expected summary %+v, got %+v", want, pedido.Resumo)
+	}
+	for _, c := range pedido.Caixas {
+		switch c.VolumeUsado {
+		case 1000:
+			if c.OcupacaoPercentual != 100 || len(c.EspacosLivres) != 0 {
+				t.Fatalf("expected full box without free spaces, got %+v", c)
+			}
+		case 500:
+			if c.OcupacaoPercentual != 50 || len(c.EspacosLivres) != 1 || c.EspacosLivres[0].Posicao.Z != 5 {
+				t.Fatalf("expected half box with the top free, got %+v", c)
+			}
+		default:
+			t.Fatalf("unexpected used volume %d", c.VolumeUsado)
+		}
+	}
+}
+
+func TestPack_ProvenOptimumClosesTheGap(t *testing.T) {
+	svc := newTestService(t)
+	// Os produtos cabem dois a dois e somam menos que o volume da caixa, então os limites calculados dão 1;
+	// mas, depois da placa, sobra uma fatia de 4 onde os dois cubos de 6 não cabem lado a lado. A busca exata
+	// comprova que 2 caixas é o mínimo, e o limite sobe para 2.
+
+	resp, err := svc.Pack(context.Background(), dto.PackingRequest{
+		Caixas: []dto.CaixaRequest{caixaInline("Cubo", 10, 10, 10)},
+		Pedidos: []dto.PedidoRequest{{PedidoID: 1, Produtos: []dto.ProdutoRequest{
+			produto("Placa", 6, 10, 10),
+			produto("B", 4, 6, 6),
+			produto("C", 4, 6, 6),
+		}}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	resumo := resp.Pedidos[0].Resumo
+	if resumo.TotalCaixas != 2 || resumo.LimitesInferiores.Volume != 1 || resumo.LimitesInferiores.Incompatibilidade != 1 {
+		t.Fatalf("expected 2 boxes against computed bounds of 1, got %+v", resumo)
+	}
+	if !resp.Pedidos[0].OtimoComprovado || resumo.LimiteInferior != 2 || resumo.Gap != 0 || resumo.GapPercentual != 0 {
+		t.Fatalf("expected the proven optimum to raise the bound to 2 with gap 0, got %+v", resumo)
+	}
+}
+
+func TestPack_RequestTimeoutReturnsPackingTimeout(t *testing.T) {
+	svc := newTestService(t, func(s *Settings) { s.RequestTimeout = time.Nanosecond })
+
+	_, err := svc.Pack(context.Background(), dto.PackingRequest{Pedidos: []dto.PedidoRequest{{
+		PedidoID: 1,
+		Produtos: []dto.ProdutoRequest{produto("Livro", 5, 10, 10)},
+	}}})
+	var se *ServiceError
+	if !errors.As(err, &se) || se.StatusCode != http.StatusGatewayTimeout || se.ErrorCode() != "PACKING_TIMEOUT" {
+		t.Fatalf("expected PACKING_TIMEOUT (504), got %v", err)
+	}
+}
+
+func TestPack_CanceledContextIsReturnedAsIs(t *testing.T) {
+	svc := newTestService(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := svc.Pack(ctx, dto.PackingRequest{Pedidos: []dto.PedidoRequest{{
+		PedidoID: 1,
+		Produtos: []dto.ProdutoRequest{produto("Livro", 5, 10, 10)},
+	}}})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestPack_BatchLargerThanPoolQueueIsAdmittedIncrementally(t *testing.T) {
+	svc := newTestService(t, func(s *Settings) { s.PoolWorkers, s.PoolQueueSize = 1, 1 })
+
+	pedidos := []dto.PedidoRequest{
+		{PedidoID: 1, Produtos: []dto.ProdutoRequest{produto("Livro", 5, 10, 10)}},
+		{PedidoID: 2, Produtos: []dto.ProdutoRequest{produto("Livro", 5, 10, 10)}},
+		{PedidoID: 3, Produtos: []dto.ProdutoRequest{produto("Livro", 5, 10, 10)}},
+	}
+	resp, err := svc.Pack(context.Background(), dto.PackingRequest{Pedidos: pedidos})
+	if err != nil {
+		t.Fatalf("expected a batch larger than the queue to be packed on an idle pool, got %v", err)
+	}
+	for i, pr := range resp.Pedidos {
+		if pr.PedidoID != pedidos[i].PedidoID || pr.Status != dto.PedidoStatusOK {
+			t.Fatalf("expected order %d to be packed, got %+v", pedidos[i].PedidoID, pr)
+		}
+	}
+}
+
+func TestPack_BusyPoolIsShed(t *testing.T) {
+	svc := newTestService(t, func(s *Settings) { s.PoolWorkers, s.PoolQueueSize = 1, 1 })
+
+	// O único worker fica ocupado e a única vaga da fila é tomada por outro lote.
+	gate := blockPool(t, svc.pool)
+	defer close(gate)
+	if err := svc.pool.newLane().submit(context.Background(), func() {}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err := svc.Pack(context.Background(), dto.PackingRequest{Pedidos: []dto.PedidoRequest{
+		{PedidoID: 1, Produtos: []dto.ProdutoRequest{produto("Livro", 5, 10, 10)}},
+	}})
+	var se *ServiceError
+	if !errors.As(err, &se) || se.StatusCode != http.StatusServiceUnavailable || se.ErrorCode() != "POOL_SATURATED" {
+		t.Fatalf("expected POOL_SATURATED (503), got %v", err)
+	}
+}
+
+func TestPack_RecordsMetrics(t *testing.T) {
+	m := metrics.New()
+	svc := newTestService(t, func(s *Settings) { s.Metrics = m })
+
+	_, err := svc.Pack(context.Background(), dto.PackingRequest{
+		PermitirParcial: true,
+		Pedidos: []dto.PedidoRequest{{
+			PedidoID: 1,
+			Produtos: []dto.ProdutoRequest{produto("Livro", 5, 10, 10), produto("Geladeira", 500, 500, 500)},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := rec.Body.String()
+	for _, line := range []string{
+		"packing_order_items_sum 2",
+		"packing_order_boxes_sum 1",
+		`packing_unpackable_items_total{reason="muito_grande"} 1`,
+		"packing_pool_queued_orders 0",
+	} {
+		if !strings.Contains(body, line+"\n") {
+			t.Fatalf("expected metric line %q in:\n%s", line, body)
+		}
+	}
+}
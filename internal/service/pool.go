@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"runtime"
+	"sync"
+
+	"github.com/warley004/packing-optimizer-api/internal/metrics"
+)
+
+// defaultPoolQueueSize é usado quando Settings.PoolQueueSize não é informado.
+const defaultPoolQueueSize = 1024
+
+// workerPool é o executor compartilhado por todos os requests do serviço: um número fixo de workers empacota os
+// pedidos de todos os lotes, então requests simultâneos dividem os núcleos em vez de disputá-los.
+//
+// Cada lote tem sua própria fila (lane) e os workers atendem as lanes em rodízio, uma tarefa de cada vez: um lote
+// grande não atrasa um pequeno que chegou depois. A soma das filas é limitada: um lote síncrono que encontra a
+// fila ocupada é recusado de imediato (503), enquanto jobs e streaming esperam por espaço.
+type workerPool struct {
+	workers int
+	limit   int
+
+	mu    sync.Mutex
+	ready *sync.Cond
+	// freed é fechado (e trocado) sempre que vagas da fila são liberadas, acordando quem espera em submit.
+	freed chan struct{}
+	// lanes são as filas com tarefas pendentes, atendidas em rodízio a partir de next.
+	lanes []*lane
+	next  int
+	// queued conta tarefas na fila e vagas reservadas; active, tarefas em execução.
+	queued int
+	active int
+	// closed recusa novas tarefas; os workers esvaziam a fila e encerram.
+	closed  bool
+	stopped sync.WaitGroup
+}
+
+// lane é a fila de um lote no pool.
+type lane struct {
+	pool  *workerPool
+	tasks []func()
+	// reserved são vagas já garantidas por reserve e ainda não usadas.
+	reserved int
+}
+
+func newWorkerPool(workers, queueSize int) *workerPool {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if queueSize <= 0 {
+		queueSize = defaultPoolQueueSize
+	}
+	p := &workerPool{
+		workers: workers,
+		limit:   queueSize,
+		freed:   make(chan struct{}),
+	}
+	p.ready = sync.NewCond(&p.mu)
+	p.stopped.Add(workers)
+	for range workers {
+		go p.work()
+	}
+	return p
+}
+
+// close recusa novas tarefas e espera os workers terminarem as que já estão na fila.
+func (p *workerPool) close() {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		p.ready.Broadcast()
+		p.notifyFreed()
+	}
+	p.mu.Unlock()
+	p.stopped.Wait()
+}
+
+// reserve garante vagas na fila para um novo lote de n tarefas ou responde 503 se o pool está saturado (load
+// shedding). Um lote maior que a fila reserva a fila inteira e o restante espera por espaço em submit, como os
+// jobs: só é recusado quando há disputa de verdade, nunca pelo tamanho.
+func (p *workerPool) reserve(n int) (*lane, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return nil, poolClosed()
+	}
+	n = min(n, p.limit)
+	if p.queued+n > p.limit {
+		return nil, poolSaturated()
+	}
+	p.queued += n
+	return &lane{pool: p, reserved: n}, nil
+}
+
+// newLane cria uma lane sem reservas: cada submit espera por espaço na fila.
+func (p *workerPool) newLane() *lane {
+	return &lane{pool: p}
+}
+
+// saturated informa se a fila está cheia.
+func (p *workerPool) saturated() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.queued >= p.limit
+}
+
+// stats é o retrato do pool exposto nas métricas.
+func (p *workerPool) stats() metrics.PoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return metrics.PoolStats{
+		Workers: p.workers,
+		Limit:   p.limit,
+		Queued:  p.queued,
+		Active:  p.active,
+		Lanes:   len(p.lanes),
+	}
+}
+
+// submit enfileira a tarefa na lane, usando uma vaga reservada ou esperando por uma livre;
+// devolve o erro do contexto se ele for cancelado durante a espera e recusa tarefas com o pool encerrado.
+func (l *lane) submit(ctx context.Context, task func()) error {
+	p := l.pool
+	p.mu.Lock()
+	for !p.closed && l.reserved == 0 && p.queued >= p.limit {
+		freed := p.freed
+		p.mu.Unlock()
+		select {
+		case <-freed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		p.mu.Lock()
+	}
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return poolClosed()
+	}
+	if l.reserved > 0 {
+		l.reserved--
+	} else {
+		p.queued++
+	}
+	if len(l.tasks) == 0 {
+		p.lanes = append(p.lanes, l)
+	}
+	l.tasks = append(l.tasks, task)
+	p.ready.Signal()
+	return nil
+}
+
+// release devolve ao pool as vagas reservadas e não usadas (lote cancelado no meio).
+func (l *lane) release() {
+	p := l.pool
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if l.reserved > 0 {
+		p.queued -= l.reserved
+		l.reserved = 0
+		p.notifyFreed()
+	}
+}
+
+func (p *workerPool) work() {
+	defer p.stopped.Done()
+	for {
+		p.mu.Lock()
+		for len(p.lanes) == 0 && !p.closed {
+			p.ready.Wait()
+		}
+		if len(p.lanes) == 0 {
+			// Encerrado e sem tarefas pendentes.
+			p.mu.Unlock()
+			return
+		}
+		if p.next >= len(p.lanes) {
+			p.next = 0
+		}
+		l := p.lanes[p.next]
+		task := l.tasks[0]
+		l.tasks[0] = nil
+		l.tasks = l.tasks[1:]
+		if len(l.tasks) == 0 {
+			// Sem tarefas, a lane sai do rodízio e next já aponta para a seguinte.
+			p.lanes = append(p.lanes[:p.next], p.lanes[p.next+1:]...)
+		} else {
+			p.next++
+		}
+		p.queued--
+		p.active++
+		p.notifyFreed()
+		p.mu.Unlock()
+
+		task()
+
+		p.mu.Lock()
+		p.active--
+		p.mu.Unlock()
+	}
+}
+
+// notifyFreed acorda quem espera por vagas; chamado com p.mu travado.
+func (p *workerPool) notifyFreed() {
+	close(p.freed)
+	p.freed = make(chan struct{})
+}
+
+func poolSaturated() error {
+	return &ServiceError{
+		StatusCode: http.StatusServiceUnavailable,
+		Code:       "POOL_SATURATED",
+		Message:    "servidor sobrecarregado: fila de empacotamento ocupada; tente novamente em instantes",
+	}
+}
+
+func poolClosed() error {
+	return &ServiceError{
+		StatusCode: http.StatusServiceUnavailable,
+		Code:       "SERVICE_SHUTTING_DOWN",
+		Message:    "serviço em encerramento; tente novamente em instantes",
+	}
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+// blockPool ocupa o único worker do pool até que o canal devolvido seja fechado.
+func blockPool(t *testing.T, p *workerPool) chan struct{} {
+	t.Helper()
+	gate, started := make(chan struct{}), make(chan struct{})
+	if err := p.newLane().submit(context.Background(), func() {
+		close(started)
+		<-gate
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	<-started
+	return gate
+}
+
+func TestWorkerPool_AlternatesBetweenLanes(t *testing.T) {
+	p := newWorkerPool(1, 10)
+	defer p.close()
+	gate := blockPool(t, p)
+
+	var mu sync.Mutex
+	var order []string
+	var wg sync.WaitGroup
+	a, b := p.newLane(), p.newLane()
+	for _, l := range []struct {
+		lane *lane
+		name string
+	}{{a, "a"}, {a, "a"}, {a, "a"}, {b, "b"}, {b, "b"}} {
+		wg.Add(1)
+		name := l.name
+		if err := l.lane.submit(context.Background(), func() {
+			defer wg.Done()
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+		}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	close(gate)
+	wg.Wait()
+
+	want := []string{"a", "b", "a", "b", "a"}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("expected round-robin order %v, got %v", want, order)
+		}
+	}
+}
+
+func TestWorkerPool_ShedsWhenQueueIsFull(t *testing.T) {
+	p := newWorkerPool(1, 2)
+	defer p.close()
+	gate := blockPool(t, p)
+	defer close(gate)
+
+	l, err := p.reserve(2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = p.reserve(1)
+	var se *ServiceError
+	if !errors.As(err, &se) || se.StatusCode != http.StatusServiceUnavailable || se.ErrorCode() != "POOL_SATURATED" {
+		t.Fatalf("expected POOL_SATURATED (503), got %v", err)
+	}
+
+	l.release()
+	if _, err := p.reserve(2); err != nil {
+		t.Fatalf("expected released slots to be reusable, got %v", err)
+	}
+}
+
+func TestWorkerPool_OversizedReservationTakesWholeQueue(t *testing.T) {
+	p := newWorkerPool(1, 2)
+	defer p.close()
+	gate := blockPool(t, p)
+	defer close(gate)
+
+	l, err := p.reserve(5)
+	if err != nil {
+		t.Fatalf("expected an oversized batch to be admitted on an idle pool, got %v", err)
+	}
+	if l.reserved != 2 || p.stats().Queued != 2 {
+		t.Fatalf("expected the whole queue (2) to be reserved, got %d reserved and %d queued", l.reserved, p.stats().Queued)
+	}
+	l.release()
+}
+
+func TestWorkerPool_SubmitWaitsForSpaceUntilCanceled(t *testing.T) {
+	p := newWorkerPool(1, 1)
+	defer p.close()
+	gate := blockPool(t, p)
+	defer close(gate)
+
+	if err := p.newLane().submit(context.Background(), func() {}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := p.newLane().submit(ctx, func() {}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected submit to wait and give up on cancel, got %v", err)
+	}
+}
+
+func TestWorkerPool_CloseDrainsQueueAndStopsWorkers(t *testing.T) {
+	p := newWorkerPool(2, 10)
+
+	var ran sync.WaitGroup
+	ran.Add(3)
+	l := p.newLane()
+	for range 3 {
+		if err := l.submit(context.Background(), ran.Done); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	p.close()
+	ran.Wait()
+
+	if err := l.submit(context.Background(), func() {}); err == nil {
+		t.Fatal("expected submit on a closed pool to fail")
+	}
+	if _, err := p.reserve(1); err == nil {
+		t.Fatal("expected reserve on a closed pool to fail")
+	}
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/warley004/packing-optimizer-api/internal/api/dto"
+)
+
+// PackStream empacota pedidos à medida que chegam, no mesmo pool de workers de Pack (que reveza os workers entre
+// os fluxos e lotes abertos). req traz só as opções do lote
+// (Pedidos é ignorado); next devolve o próximo pedido e emit recebe cada resposta assim que o pedido termina,
+// na ordem de conclusão.
+//
+// next sinaliza o fim com io.EOF. Um *ServiceError rejeita apenas aquele pedido, que é emitido com status "erro";
+// qualquer outro erro interrompe a leitura, aguarda os pedidos em andamento e é devolvido. PackStream só retorna
+// depois da última chamada a next, que precisa retornar quando ctx é cancelado (como a leitura do corpo de um
+// request encerrado); com o contexto cancelado, o erro devolvido é o do contexto.
+// Pedidos que não podem ser empacotados também são emitidos com status "erro", como no modo "melhor_esforco".
+//
+// No máximo um pedido por worker do pool fica entre a leitura e emit: se emit (o cliente) fica lento, a leitura
+// para e a memória se mantém constante, qualquer que seja o tamanho do fluxo, sem prender os workers do pool.
+// Erros nas opções do lote (caixas) e o pool saturado (503) são devolvidos antes de qualquer leitura ou emit.
+func (s *PackingService) PackStream(ctx context.Context, req dto.PackingRequest, next func() (dto.PedidoRequest, error), emit func(dto.PedidoResponse) error) error {
+	opts, err := s.batchOptions(req)
+	if err != nil {
+		return err
+	}
+	if s.pool.saturated() {
+		return poolSaturated()
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	workers := s.pool.workers
+	jobCh := make(chan orderJob, workers)
+
+	// A leitura roda em paralelo; readDone fecha quando ela termina e só então readErr pode ser lido.
+	// PackStream sempre espera a leitura terminar: next (o corpo do request) não é usado depois do retorno.
+	readDone := make(chan struct{})
+	var readErr error
+	go func() {
+		defer close(readDone)
+		defer close(jobCh)
+		for idx := 0; ctx.Err() == nil; idx++ {
+			pedido, err := next()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			var se *ServiceError
+			if err != nil && !errors.As(err, &se) {
+				readErr = err
+				return
+			}
+			select {
+			case jobCh <- orderJob{index: idx, pedido: pedido, err: err}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	defer func() {
+		cancel()
+		<-readDone
+	}()
+
+	for res := range s.runOrders(ctx, s.pool.newLane(), workers, jobCh, opts) {
+		pr := res.pedido
+		if res.err != nil {
+			pr = failedOrder(res.pedidoID, res.err)
+		}
+		if err := emit(pr); err != nil {
+			return err
+		}
+	}
+
+	// Com o contexto cancelado, o canal de resultados fecha antes do fim da leitura: o cancelamento prevalece.
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	<-readDone
+	return readErr
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"runtime"
+	"sync/atomic"
+	"testing"
+
+	"github.com/warley004/packing-optimizer-api/internal/api/dto"
+)
+
+// pedidos devolve um next que entrega os pedidos em sequência e termina com io.EOF.
+func pedidos(list ...dto.PedidoRequest) func() (dto.PedidoRequest, error) {
+	i := 0
+	return func() (dto.PedidoRequest, error) {
+		if i == len(list) {
+			return dto.PedidoRequest{}, io.EOF
+		}
+		i++
+		return list[i-1], nil
+	}
+}
+
+func TestPackStream_EmitsOneResponsePerOrder(t *testing.T) {
+	svc := newTestService(t)
+	next := pedidos(
+		dto.PedidoRequest{PedidoID: 1, Produtos: []dto.ProdutoRequest{produto("PS5", 40, 10, 25)}},
+		dto.PedidoRequest{PedidoID: 2, Produtos: []dto.ProdutoRequest{produto("Geladeira", 500, 500, 500)}},
+		dto.PedidoRequest{PedidoID: 3, Produtos: []dto.ProdutoRequest{produto("Volante", 40, 30, 30)}},
+	)
+
+	got := make(map[int64]dto.PedidoResponse)
+	err := svc.PackStream(context.Background(), dto.PackingRequest{}, next, func(pr dto.PedidoResponse) error {
+		got[pr.PedidoID] = pr
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(got) != 3 {
+		t.Fatalf("expected 3 responses, got %d", len(got))
+	}
+	if got[1].Status != dto.PedidoStatusOK || got[3].Status != dto.PedidoStatusOK {
+		t.Fatalf("expected orders 1 and 3 to be packed, got %+v and %+v", got[1], got[3])
+	}
+	if got[2].Status != dto.PedidoStatusErro || got[2].Erro == nil || got[2].Erro.Code != "PACKING_ERROR" {
+		t.Fatalf("expected order 2 to fail in-band, got %+v", got[2])
+	}
+}
+
+func TestPackStream_RejectedOrderAndReadError(t *testing.T) {
+	svc := newTestService(t)
+	readErr := errors.New("json malformado")
+	calls := 0
+	next := func() (dto.PedidoRequest, error) {
+		calls++
+		switch calls {
+		case 1:
+			return dto.PedidoRequest{PedidoID: 7}, &ServiceError{StatusCode: http.StatusBadRequest, Code: "VALIDATION_ERROR", Message: "produtos obrigatório"}
+		case 2:
+			return dto.PedidoRequest{PedidoID: 8, Produtos: []dto.ProdutoRequest{produto("PS5", 40, 10, 25)}}, nil
+		}
+		return dto.PedidoRequest{}, readErr
+	}
+
+	var got []dto.PedidoResponse
+	err := svc.PackStream(context.Background(), dto.PackingRequest{}, next, func(pr dto.PedidoResponse) error {
+		got = append(got, pr)
+		return nil
+	})
+	if !errors.Is(err, readErr) {
+		t.Fatalf("expected read error, got %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected orders read before the error to be emitted, got %d", len(got))
+	}
+	for _, pr := range got {
+		if pr.PedidoID == 7 && (pr.Status != dto.PedidoStatusErro || pr.Erro.Code != "VALIDATION_ERROR") {
+			t.Fatalf("expected rejected order to be emitted with its error, got %+v", pr)
+		}
+	}
+}
+
+func TestPackStream_InvalidBatchOptionsFailBeforeReading(t *testing.T) {
+	svc := newTestService(t)
+	err := svc.PackStream(context.Background(), dto.PackingRequest{CaixasPermitidas: []string{"Inexistente"}},
+		func() (dto.PedidoRequest, error) {
+			t.Fatal("next should not be called")
+			return dto.PedidoRequest{}, io.EOF
+		},
+		func(dto.PedidoResponse) error {
+			t.Fatal("emit should not be called")
+			return nil
+		})
+	var se *ServiceError
+	if !errors.As(err, &se) {
+		t.Fatalf("expected service error, got %v", err)
+	}
+}
+
+func TestPackStream_ReadsAheadOnlyAFewOrders(t *testing.T) {
+	svc := newTestService(t)
+	const total = 200
+	var read, emitted, maxAhead int64
+
+	next := func() (dto.PedidoRequest, error) {
+		n := atomic.AddInt64(&read, 1)
+		if n > total {
+			return dto.PedidoRequest{}, io.EOF
+		}
+		return dto.PedidoRequest{PedidoID: n, Produtos: []dto.ProdutoRequest{produto("PS5", 40, 10, 25)}}, nil
+	}
+	emit := func(dto.PedidoResponse) error {
+		done := atomic.AddInt64(&emitted, 1)
+		if ahead := min(atomic.LoadInt64(&read), total) - done; ahead > maxAhead {
+			maxAhead = ahead
+		}
+		runtime.Gosched()
+		return nil
+	}
+
+	if err := svc.PackStream(context.Background(), dto.PackingRequest{}, next, emit); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if emitted != total {
+		t.Fatalf("expected %d responses, got %d", total, emitted)
+	}
+	// Em trânsito: um pedido por worker no canal de leitura, até um por worker entre o pool e emit e um lido aguardando envio.
+	if limit := int64(3*runtime.NumCPU() + 2); maxAhead > limit {
+		t.Fatalf("expected at most %d orders read ahead of emit, got %d", limit, maxAhead)
+	}
+}
+
+func TestPackStream_CanceledWhileReading(t *testing.T) {
+	svc := newTestService(t)
+
+	for name, blocking := range map[string]bool{"leitura falha": false, "leitura bloqueada": true} {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			var calls int64
+			next := func() (dto.PedidoRequest, error) {
+				n := atomic.AddInt64(&calls, 1)
+				if n <= 3 {
+					return dto.PedidoRequest{PedidoID: n, Produtos: []dto.ProdutoRequest{produto("PS5", 40, 10, 25)}}, nil
+				}
+				// O cliente desconecta no meio do corpo.
+				cancel()
+				if blocking {
+					<-ctx.Done()
+				}
+				return dto.PedidoRequest{}, io.ErrUnexpectedEOF
+			}
+
+			err := svc.PackStream(ctx, dto.PackingRequest{}, next, func(dto.PedidoResponse) error { return nil })
+			if !errors.Is(err, context.Canceled) {
+				t.Fatalf("expected context.Canceled, got %v", err)
+			}
+			if got := atomic.LoadInt64(&calls); got != 4 {
+				t.Fatalf("expected reading to stop after the cancellation, got %d calls", got)
+			}
+		})
+	}
+}